@@ -4,17 +4,21 @@ import (
 	"bytes"
 	"context"
 	"crypto/hmac"
+	"crypto/rand"
 	"crypto/sha512"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"math/rand"
+	"io"
 	"net/http"
 	"net/url"
 	"reflect"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -28,20 +32,34 @@ var EndPoints = map[string]string{
 	"PROD3D": "https://virtualpospaymentgateway.akbank.com/securepay",
 }
 
-var CurrencyCode = map[string]int{
-	"TRY": 949,
-	"YTL": 949,
-	"TRL": 949,
-	"TL":  949,
-	"USD": 840,
-	"EUR": 978,
-	"GBP": 826,
-	"JPY": 392,
+var CurrencyCode = map[string]int{}
+
+var ResponseHashParams = []string{"txnCode", "responseCode", "hostResponseCode", "merchantSafeId", "terminalSafeId", "orderId", "authCode", "rrn", "amount", "currencyCode", "installCount", "txnDateTime"}
+
+var ResponseHashObjects = []string{"terminal", "order", "transaction", "card", "secureTransaction", "reward", "campaign", "interest", "subMerchant", "b2b", "recurring", "plannedDate", "linkDetail", "header"}
+
+var ErrResponseSignature = errors.New("invalid response signature")
+
+type Doer interface {
+	Do(*http.Request) (*http.Response, error)
 }
 
+type Option func(*API)
+
 type API struct {
-	Mode      string
-	SecretKey string
+	Mode           string
+	MerchantId     string
+	TerminalId     string
+	SecretKey      string
+	BaseURL        string
+	GatewayURL     string
+	Timeout        time.Duration
+	Client         Doer
+	Rand           io.Reader
+	Clock          Clock
+	SubMerchants   *SubMerchantRegistry
+	VerifyResponse bool
+	randMu         sync.Mutex
 }
 
 type Request struct {
@@ -55,6 +73,7 @@ type Request struct {
 	RequestDateTime   *string            `json:"requestDateTime,omitempty" form:"requestDateTime,omitempty"`
 	RandomNumber      *string            `json:"randomNumber,omitempty" form:"randomNumber,omitempty"`
 	InstitutionCode   *string            `json:"institutionCode,omitempty"`
+	ReferenceId       *string            `json:"referenceId,omitempty"`
 	Terminal          *Terminal          `json:"terminal,omitempty"`
 	Card              *Card              `json:"card,omitempty"`
 	InsurancePan      *InsurancePan      `json:"insurancePan,omitempty"`
@@ -69,6 +88,7 @@ type Request struct {
 	SubMerchant       *SubMerchant       `json:"subMerchant,omitempty"`
 	B2B               *B2B               `json:"b2b,omitempty"`
 	SGK               *SGK               `json:"sgk,omitempty"`
+	Report            *Report            `json:"report,omitempty"`
 	Hash              *string            `json:",omitempty" form:"hash,omitempty"`
 }
 
@@ -99,6 +119,7 @@ type Response struct {
 	Token            *string        `json:"token,omitempty"`
 	Header           *Header        `json:"header,omitempty"`
 	LinkDetail       *LinkDetail    `json:"linkDetail,omitempty"`
+	LinkDetailList   []*LinkDetail  `json:"linkDetailList,omitempty"`
 	InstallmentList  []*Installment `json:"installmentConditionList,omitempty"`
 	TxnDetailList    []*TxnDetail   `json:"txnDetailList,omitempty"`
 	Error            *Error         `json:"error,omitempty"`
@@ -113,6 +134,7 @@ type Card struct {
 	CardNumber     *string `json:"cardNumber,omitempty" form:"creditCard,omitempty"`
 	CardCode       *string `json:"cvv2,omitempty" form:"cvv,omitempty"`
 	CardExpiry     *string `json:"expireDate,omitempty" form:"expiredDate,omitempty"`
+	BinNumber      *string `json:"binNumber,omitempty"`
 }
 
 type Customer struct {
@@ -157,7 +179,7 @@ type SecureTransaction struct {
 }
 
 type SGK struct {
-	SurchargeAmount *float `json:"surchargeAmount,omitempty"`
+	SurchargeAmount *Money `json:"surchargeAmount,omitempty"`
 }
 
 type SubMerchant struct {
@@ -188,91 +210,96 @@ type Installment struct {
 
 type Interest struct {
 	InterestRate   *float `json:"interestRate,omitempty"`
-	InterestAmount *float `json:"interestAmount,omitempty"`
+	InterestAmount *Money `json:"interestAmount,omitempty"`
 }
 
 type LinkDetail struct {
-	LinkTransferType  *string `json:"linkTransferType,omitempty"`
-	MobilePhoneNumber *string `json:"mobilePhoneNumber,omitempty"`
-	Email             *string `json:"email,omitempty"`
-	LinkValidTerm     *float  `json:"linkValidTerm,omitempty"`
-	Amount            *float  `json:"amount,omitempty"`
-	Currency          *int    `json:"currencyCode,omitempty"`
-	InstallmentCount  *float  `json:"installmentCount,omitempty"`
-	ReferenceId       *string `json:"referenceId,omitempty"`
-	ErrorCode         *string `json:"errorCode,omitempty"`
-	ErrorMessage      *string `json:"errorMessage,omitempty"`
-	LinkExpireDate    *string `json:"linkExpireDate,omitempty"`
-	LinkStatus        *string `json:"linkStatus,omitempty"`
-	InstallmentType   *float  `json:"installmentType,omitempty"`
+	LinkTransferType  *string   `json:"linkTransferType,omitempty"`
+	MobilePhoneNumber *string   `json:"mobilePhoneNumber,omitempty"`
+	Email             *string   `json:"email,omitempty"`
+	LinkValidTerm     *float    `json:"linkValidTerm,omitempty"`
+	Amount            *Money    `json:"amount,omitempty"`
+	Currency          *Currency `json:"currencyCode,omitempty"`
+	InstallmentCount  *float    `json:"installmentCount,omitempty"`
+	ReferenceId       *string   `json:"referenceId,omitempty"`
+	ErrorCode         *string   `json:"errorCode,omitempty"`
+	ErrorMessage      *string   `json:"errorMessage,omitempty"`
+	LinkExpireDate    *string   `json:"linkExpireDate,omitempty"`
+	LinkStatus        *string   `json:"linkStatus,omitempty"`
+	InstallmentType   *float    `json:"installmentType,omitempty"`
+}
+
+type Report struct {
+	StartDateTime *string `json:"startDateTime,omitempty"`
+	EndDateTime   *string `json:"endDateTime,omitempty"`
 }
 
 type Reward struct {
-	CcbRewardAmount        *float  `json:"ccbRewardAmount,omitempty" form:"ccbRewardAmount,omitempty"`
-	PcbRewardAmount        *float  `json:"pcbRewardAmount,omitempty" form:"pcbRewardAmount,omitempty"`
-	XcbRewardAmount        *float  `json:"xcbRewardAmount,omitempty" form:"xcbRewardAmount,omitempty"`
-	CcbEarnedRewardAmount  *float  `json:"ccbEarnedRewardAmount,omitempty"`
-	CcbBalanceRewardAmount *float  `json:"ccbBalanceRewardAmount,omitempty"`
+	CcbRewardAmount        *Money  `json:"ccbRewardAmount,omitempty" form:"ccbRewardAmount,omitempty"`
+	PcbRewardAmount        *Money  `json:"pcbRewardAmount,omitempty" form:"pcbRewardAmount,omitempty"`
+	XcbRewardAmount        *Money  `json:"xcbRewardAmount,omitempty" form:"xcbRewardAmount,omitempty"`
+	CcbEarnedRewardAmount  *Money  `json:"ccbEarnedRewardAmount,omitempty"`
+	CcbBalanceRewardAmount *Money  `json:"ccbBalanceRewardAmount,omitempty"`
 	CcbRewardDesc          *string `json:"ccbRewardDesc,omitempty"`
-	PcbEarnedRewardAmount  *float  `json:"pcbEarnedRewardAmount,omitempty"`
-	PcbBalanceRewardAmount *float  `json:"pcbBalanceRewardAmount,omitempty"`
+	PcbEarnedRewardAmount  *Money  `json:"pcbEarnedRewardAmount,omitempty"`
+	PcbBalanceRewardAmount *Money  `json:"pcbBalanceRewardAmount,omitempty"`
 	PcbRewardDesc          *string `json:"pcbRewardDesc,omitempty"`
-	XcbEarnedRewardAmount  *float  `json:"xcbEarnedRewardAmount,omitempty"`
-	XcbBalanceRewardAmount *float  `json:"xcbBalanceRewardAmount,omitempty"`
+	XcbEarnedRewardAmount  *Money  `json:"xcbEarnedRewardAmount,omitempty"`
+	XcbBalanceRewardAmount *Money  `json:"xcbBalanceRewardAmount,omitempty"`
 	XcbRewardDesc          *string `json:"xcbRewardDesc,omitempty"`
 }
 
 type Transaction struct {
-	Amount      *float  `json:"amount,omitempty" form:"amount,omitempty"`
-	Currency    *int    `json:"currencyCode,omitempty" form:"currencyCode,omitempty"`
-	MotoInd     *int    `json:"motoInd,omitempty"`
-	Installment *int    `json:"installCount,omitempty" form:"installCount,omitempty"`
-	AuthCode    *string `json:"authCode,omitempty"`
-	Rrn         *string `json:"rrn,omitempty"`
-	BatchNumber *int    `json:"batchNumber,omitempty"`
-	Stan        *int    `json:"stan,omitempty"`
+	Amount      *Money    `json:"amount,omitempty" form:"amount,omitempty"`
+	Currency    *Currency `json:"currencyCode,omitempty" form:"currencyCode,omitempty"`
+	MotoInd     *int      `json:"motoInd,omitempty"`
+	Installment *int      `json:"installCount,omitempty" form:"installCount,omitempty"`
+	AuthCode    *string   `json:"authCode,omitempty"`
+	Rrn         *string   `json:"rrn,omitempty"`
+	BatchNumber *int      `json:"batchNumber,omitempty"`
+	Stan        *int      `json:"stan,omitempty"`
 }
 
 type TxnDetail struct {
-	TxnCode                    *string `json:"txnCode,omitempty"`
-	ResponseCode               *string `json:"responseCode,omitempty"`
-	ResponseMessage            *string `json:"responseMessage,omitempty"`
-	HostResponseCode           *string `json:"hostResponseCode,omitempty"`
-	HostMessage                *string `json:"hostMessage,omitempty"`
-	TxnDateTime                *string `json:"txnDateTime,omitempty"`
-	PlannedDateTime            *string `json:"plannedDateTime,omitempty"`
-	TerminalSafeId             *string `json:"terminalSafeId,omitempty"`
-	MerchantSafeId             *string `json:"merchantSafeId,omitempty"`
-	OrderId                    *string `json:"orderId,omitempty"`
-	OrderTrackId               *string `json:"orderTrackId,omitempty"`
-	AuthCode                   *string `json:"authCode,omitempty"`
-	Rrn                        *string `json:"rrn,omitempty"`
-	BatchNumber                *int    `json:"batchNumber,omitempty"`
-	Stan                       *int    `json:"stan,omitempty"`
-	SettlementId               *string `json:"settlementId,omitempty"`
-	TxnStatus                  *string `json:"txnStatus,omitempty"`
-	Amount                     *float  `json:"amount,omitempty"`
-	Currency                   *int    `json:"currencyCode,omitempty"`
-	MotoInd                    *int    `json:"motoInd,omitempty"`
-	Installment                *int    `json:"installCount,omitempty"`
-	CcbRewardAmount            *float  `json:"ccbRewardAmount,omitempty"`
-	PcbRewardAmount            *float  `json:"pcbRewardAmount,omitempty"`
-	XcbRewardAmount            *float  `json:"xcbRewardAmount,omitempty"`
-	PreAuthStatus              *string `json:"preAuthStatus,omitempty"`
-	PreAuthCloseAmount         *float  `json:"preAuthCloseAmount,omitempty"`
-	PreAuthPartialCancelAmount *float  `json:"preAuthPartialCancelAmount,omitempty"`
-	PreAuthCloseDate           *string `json:"preAuthCloseDate,omitempty"`
-	MaskedCardNumber           *string `json:"maskedCardNumber,omitempty"`
-	RecurringOrder             *int    `json:"recurringOrder,omitempty"`
-	RequestType                *string `json:"requestType,omitempty"`
-	RequestStatus              *string `json:"requestStatus,omitempty"`
-	CancelDate                 *string `json:"cancelDate,omitempty"`
-	TryCount                   *int    `json:"tryCount,omitempty"`
-	Xid                        *string `json:"xid,omitempty"`
-	PaymentModel               *string `json:"paymentModel,omitempty"`
-	Eci                        *string `json:"eci,omitempty"`
-	SecureData                 *string `json:"secureData,omitempty"`
-	OrgOrderId                 *string `json:"orgOrderId,omitempty"`
+	TxnCode                    *string   `json:"txnCode,omitempty"`
+	ResponseCode               *string   `json:"responseCode,omitempty"`
+	ResponseMessage            *string   `json:"responseMessage,omitempty"`
+	HostResponseCode           *string   `json:"hostResponseCode,omitempty"`
+	HostMessage                *string   `json:"hostMessage,omitempty"`
+	TxnDateTime                *string   `json:"txnDateTime,omitempty"`
+	PlannedDateTime            *string   `json:"plannedDateTime,omitempty"`
+	TerminalSafeId             *string   `json:"terminalSafeId,omitempty"`
+	MerchantSafeId             *string   `json:"merchantSafeId,omitempty"`
+	OrderId                    *string   `json:"orderId,omitempty"`
+	OrderTrackId               *string   `json:"orderTrackId,omitempty"`
+	AuthCode                   *string   `json:"authCode,omitempty"`
+	Rrn                        *string   `json:"rrn,omitempty"`
+	BatchNumber                *int      `json:"batchNumber,omitempty"`
+	Stan                       *int      `json:"stan,omitempty"`
+	SettlementId               *string   `json:"settlementId,omitempty"`
+	TxnStatus                  *string   `json:"txnStatus,omitempty"`
+	Amount                     *Money    `json:"amount,omitempty"`
+	Currency                   *Currency `json:"currencyCode,omitempty"`
+	MotoInd                    *int      `json:"motoInd,omitempty"`
+	Installment                *int      `json:"installCount,omitempty"`
+	CcbRewardAmount            *Money    `json:"ccbRewardAmount,omitempty"`
+	PcbRewardAmount            *Money    `json:"pcbRewardAmount,omitempty"`
+	XcbRewardAmount            *Money    `json:"xcbRewardAmount,omitempty"`
+	PreAuthStatus              *string   `json:"preAuthStatus,omitempty"`
+	PreAuthCloseAmount         *Money    `json:"preAuthCloseAmount,omitempty"`
+	PreAuthPartialCancelAmount *Money    `json:"preAuthPartialCancelAmount,omitempty"`
+	PreAuthCloseDate           *string   `json:"preAuthCloseDate,omitempty"`
+	MaskedCardNumber           *string   `json:"maskedCardNumber,omitempty"`
+	RecurringOrder             *int      `json:"recurringOrder,omitempty"`
+	RequestType                *string   `json:"requestType,omitempty"`
+	RequestStatus              *string   `json:"requestStatus,omitempty"`
+	CancelDate                 *string   `json:"cancelDate,omitempty"`
+	TryCount                   *int      `json:"tryCount,omitempty"`
+	Xid                        *string   `json:"xid,omitempty"`
+	PaymentModel               *string   `json:"paymentModel,omitempty"`
+	Eci                        *string   `json:"eci,omitempty"`
+	SecureData                 *string   `json:"secureData,omitempty"`
+	OrgOrderId                 *string   `json:"orgOrderId,omitempty"`
 }
 
 type Error struct {
@@ -329,7 +356,7 @@ func reflector(values url.Values, val reflect.Value) error {
 			}
 			sv = sv.Elem()
 		}
-		if sv.Kind() == reflect.Struct {
+		if sv.Kind() == reflect.Struct && sv.Type() != reflect.TypeOf(Money{}) {
 			if err := reflector(values, sv); err != nil {
 				return err
 			}
@@ -347,6 +374,9 @@ func reflector(values url.Values, val reflect.Value) error {
 				} else if len(ts) == 1 {
 					values.Add(name, value)
 				}
+			} else if sv.Type() == reflect.TypeOf(Currency(0)) {
+				// the bank expects the numeric ISO 4217 code, not Currency.String()
+				values.Add(name, strconv.Itoa(int(sv.Int())))
 			} else {
 				value := String(sv)
 				if len(ts) > 1 && ts[1] == "omitempty" && value != "" {
@@ -362,16 +392,97 @@ func reflector(values url.Values, val reflect.Value) error {
 	return nil
 }
 
-func Api(merchantid, terminalid, secretkey string) (*API, *Request) {
+func New(merchantid, terminalid, secretkey string, opts ...Option) *API {
 	api := new(API)
+	api.MerchantId = merchantid
+	api.TerminalId = terminalid
 	api.SecretKey = secretkey
+	for _, opt := range opts {
+		opt(api)
+	}
+	return api
+}
+
+func Api(merchantid, terminalid, secretkey string) (*API, *Request) {
+	api := New(merchantid, terminalid, secretkey)
+	return api, api.NewRequest()
+}
+
+func WithMode(mode string) Option {
+	return func(api *API) {
+		api.Mode = mode
+	}
+}
+
+func WithHTTPClient(client *http.Client) Option {
+	return func(api *API) {
+		// a nil *http.Client would make a non-nil Doer and bypass the default client
+		if client != nil {
+			api.Client = client
+		}
+	}
+}
+
+func WithDoer(doer Doer) Option {
+	return func(api *API) {
+		api.Client = doer
+	}
+}
+
+func WithRandom(random io.Reader) Option {
+	return func(api *API) {
+		api.Rand = random
+	}
+}
+
+func WithTimeout(timeout time.Duration) Option {
+	return func(api *API) {
+		api.Timeout = timeout
+	}
+}
+
+func WithBaseURL(baseurl string) Option {
+	return func(api *API) {
+		api.BaseURL = strings.TrimSuffix(baseurl, "/")
+	}
+}
+
+func WithGatewayURL(gatewayurl string) Option {
+	return func(api *API) {
+		api.GatewayURL = gatewayurl
+	}
+}
+
+func WithVerifyResponse(verify bool) Option {
+	return func(api *API) {
+		api.VerifyResponse = verify
+	}
+}
+
+func (api *API) NewRequest() *Request {
+	merchantid := api.MerchantId
+	terminalid := api.TerminalId
 	version := "1.00"
 	req := new(Request)
 	req.Version = &version
 	req.Terminal = new(Terminal)
 	req.Terminal.MerchantSafeId = &merchantid
 	req.Terminal.TerminalSafeId = &terminalid
-	return api, req
+	return req
+}
+
+func (api *API) Endpoint() string {
+	if api.BaseURL != "" {
+		return api.BaseURL
+	}
+	return EndPoints[api.Mode]
+}
+
+func (api *API) Gateway() string {
+	if api.GatewayURL != "" {
+		return api.GatewayURL
+	}
+	return EndPoints[api.Mode+"3D"]
 }
 
 func B64(data string) (hash string) {
@@ -402,22 +513,95 @@ func (api *API) Hash3D(req url.Values, params []string) string {
 	return api.Hash([]byte(plain))
 }
 
-func (api *API) Random(n int) string {
+func (api *API) VerifyHash(header string, body []byte) bool {
+	if header != "" {
+		return hmac.Equal([]byte(api.Hash(body)), []byte(header))
+	}
+	var data map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&data); err != nil {
+		return false
+	}
+	values := make(url.Values)
+	flatten(values, data)
+	hash := values.Get("hash")
+	if hash == "" {
+		return false
+	}
+	params := ResponseHashParams
+	if hashparams := values.Get("hashParams"); hashparams != "" {
+		params = HashParams(hashparams)
+	}
+	return hmac.Equal([]byte(api.Hash3D(values, params)), []byte(hash))
+}
+
+func HashParams(hashparams string) []string {
+	// hashParams is "+" separated; an unescaped "+" arrives as a space after form decoding
+	return strings.FieldsFunc(hashparams, func(r rune) bool {
+		return r == '+' || r == ':' || r == ' '
+	})
+}
+
+func flatten(values url.Values, data map[string]interface{}) {
+	// top-level values win, then sub-objects in a fixed order, so a repeated key always hashes the same value
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	objects := []map[string]interface{}{}
+	for _, k := range ResponseHashObjects {
+		if v, ok := data[k].(map[string]interface{}); ok {
+			objects = append(objects, v)
+		}
+	}
+	for _, k := range keys {
+		switch v := data[k].(type) {
+		case map[string]interface{}:
+			if !slices.Contains(ResponseHashObjects, k) {
+				objects = append(objects, v)
+			}
+		case []interface{}, nil:
+		default:
+			if values.Get(k) == "" {
+				values.Set(k, fmt.Sprint(v))
+			}
+		}
+	}
+	for _, object := range objects {
+		flatten(values, object)
+	}
+}
+
+func (api *API) Random(n int) (string, error) {
 	const alphanum = "0123456789ABCDEF"
-	var bytes = make([]byte, n)
-	source := rand.NewSource(time.Now().UnixNano())
-	rand := rand.New(source)
-	rand.Read(bytes)
-	for i, b := range bytes {
-		bytes[i] = alphanum[b%byte(len(alphanum))]
+	var bytes = make([]byte, (n+1)/2)
+	if api.Rand != nil {
+		api.randMu.Lock()
+		_, err := io.ReadFull(api.Rand, bytes)
+		api.randMu.Unlock()
+		if err != nil {
+			return "", err
+		}
+	} else if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	random := make([]byte, 0, len(bytes)*2)
+	for _, b := range bytes {
+		random = append(random, alphanum[b>>4], alphanum[b&0x0f])
 	}
-	return string(bytes)
+	return string(random[:n]), nil
 }
 
 func (api *API) SetMode(mode string) {
 	api.Mode = mode
 }
 
+func (api *API) SetVerifyResponse(verify bool) {
+	api.VerifyResponse = verify
+}
+
 func (req *Request) SetLang(lang string) {
 	req.Lang = &lang
 }
@@ -444,25 +628,33 @@ func (req *Request) SetCardCode(cardcode string) {
 	req.Card.CardCode = &cardcode
 }
 
-func (req *Request) SetAmount(price, currency string) {
+func (req *Request) SetAmount(price, currency string) error {
 	if req.Transaction == nil {
 		req.Transaction = new(Transaction)
 	}
-	if parse, err := strconv.ParseFloat(price, 32); err == nil {
-		amount := float(parse)
-		code := CurrencyCode[currency]
-		req.Transaction.Amount = &amount
-		req.Transaction.Currency = &code
+	code, err := LookupCurrency(currency)
+	if err != nil {
+		return err
+	}
+	amount, err := ParseMoney(price, code.Alpha())
+	if err != nil {
+		return err
 	}
+	req.Transaction.Amount = &amount
+	req.Transaction.Currency = &code
+	return nil
 }
 
-func (req *Request) SetInstallment(installment string) {
+func (req *Request) SetInstallment(installment string) error {
+	parse, err := strconv.Atoi(installment)
+	if err != nil {
+		return errors.New("invalid installment: " + installment)
+	}
 	if req.Transaction == nil {
 		req.Transaction = new(Transaction)
 	}
-	if parse, err := strconv.Atoi(installment); err == nil {
-		req.Transaction.Installment = &parse
-	}
+	req.Transaction.Installment = &parse
+	return nil
 }
 
 func (req *Request) SetCustomerIPv4(ipaddress string) {
@@ -486,68 +678,193 @@ func (req *Request) SetOrderId(orderid string) {
 	req.Order.OrderId = &orderid
 }
 
-func (api *API) PreAuth(ctx context.Context, req *Request) (Response, error) {
-	date := time.Now().Format("2006-01-02T15:04:05.000")
-	rnd := api.Random(128)
-	code := "1004"
-	motoInd := 0
+func (req *Request) Clone() *Request {
+	if req == nil {
+		return nil
+	}
+	clone := *req
+	if req.Terminal != nil {
+		terminal := *req.Terminal
+		clone.Terminal = &terminal
+	}
+	if req.Card != nil {
+		card := *req.Card
+		clone.Card = &card
+	}
+	if req.InsurancePan != nil {
+		insurancepan := *req.InsurancePan
+		clone.InsurancePan = &insurancepan
+	}
+	if req.Order != nil {
+		order := *req.Order
+		clone.Order = &order
+	}
+	if req.Reward != nil {
+		reward := *req.Reward
+		clone.Reward = &reward
+	}
+	if req.Transaction != nil {
+		transaction := *req.Transaction
+		clone.Transaction = &transaction
+	}
+	if req.Customer != nil {
+		customer := *req.Customer
+		clone.Customer = &customer
+	}
+	if req.Recurring != nil {
+		recurring := *req.Recurring
+		clone.Recurring = &recurring
+	}
+	if req.PlannedDate != nil {
+		planneddate := *req.PlannedDate
+		clone.PlannedDate = &planneddate
+	}
+	if req.PayByLink != nil {
+		paybylink := *req.PayByLink
+		clone.PayByLink = &paybylink
+	}
+	if req.SecureTransaction != nil {
+		securetransaction := *req.SecureTransaction
+		clone.SecureTransaction = &securetransaction
+	}
+	if req.SubMerchant != nil {
+		submerchant := *req.SubMerchant
+		clone.SubMerchant = &submerchant
+	}
+	if req.B2B != nil {
+		b2b := *req.B2B
+		clone.B2B = &b2b
+	}
+	if req.SGK != nil {
+		sgk := *req.SGK
+		clone.SGK = &sgk
+	}
+	if req.Report != nil {
+		report := *req.Report
+		clone.Report = &report
+	}
+	return &clone
+}
+
+func (api *API) prepare(req *Request, code string) (*Request, error) {
+	rnd, err := api.Random(128)
+	if err != nil {
+		return nil, err
+	}
+	req = req.Clone()
+	date := api.Now().Format(DateTimeLayout)
 	req.RequestDateTime = &date
 	req.RandomNumber = &rnd
 	req.TxnCode = &code
+	req.Hash = nil
+	return req, nil
+}
+
+func (api *API) PreAuth(ctx context.Context, req *Request) (res Response, err error) {
+	if err := req.ValidateAt("1004", api.Now()); err != nil {
+		return res, err
+	}
+	if req, err = api.prepare(req, "1004"); err != nil {
+		return res, err
+	}
+	motoInd := 0
 	req.Transaction.MotoInd = &motoInd
 	return api.Transaction(ctx, req)
 }
 
-func (api *API) Auth(ctx context.Context, req *Request) (Response, error) {
-	date := time.Now().Format("2006-01-02T15:04:05.000")
-	rnd := api.Random(128)
-	code := "1000"
+func (api *API) Auth(ctx context.Context, req *Request) (res Response, err error) {
+	if err := req.ValidateAt("1000", api.Now()); err != nil {
+		return res, err
+	}
+	if req, err = api.prepare(req, "1000"); err != nil {
+		return res, err
+	}
 	motoInd := 0
-	req.RequestDateTime = &date
-	req.RandomNumber = &rnd
-	req.TxnCode = &code
 	req.Transaction.MotoInd = &motoInd
 	return api.Transaction(ctx, req)
 }
 
-func (api *API) PreAuth3D(ctx context.Context, req *Request) (Response, error) {
-	date := time.Now().Format("2006-01-02T15:04:05.000")
-	rnd := api.Random(128)
-	code := "1004"
+func (api *API) PreAuthMoto(ctx context.Context, req *Request) (res Response, err error) {
+	if err := req.ValidateAt("1004", api.Now()); err != nil {
+		return res, err
+	}
+	if req, err = api.prepare(req, "1004"); err != nil {
+		return res, err
+	}
+	motoInd := 1
+	if req.Customer != nil {
+		req.Customer.IpAddress = nil
+	}
+	req.PaymentModel = nil
+	req.SecureTransaction = nil
+	req.Transaction.MotoInd = &motoInd
+	return api.Transaction(ctx, req)
+}
+
+func (api *API) AuthMoto(ctx context.Context, req *Request) (res Response, err error) {
+	if err := req.ValidateAt("1000", api.Now()); err != nil {
+		return res, err
+	}
+	if req, err = api.prepare(req, "1000"); err != nil {
+		return res, err
+	}
+	motoInd := 1
+	if req.Customer != nil {
+		req.Customer.IpAddress = nil
+	}
+	req.PaymentModel = nil
+	req.SecureTransaction = nil
+	req.Transaction.MotoInd = &motoInd
+	return api.Transaction(ctx, req)
+}
+
+func (api *API) PreAuth3D(ctx context.Context, req *Request) (res Response, err error) {
+	if err := req.ValidateAt("1004", api.Now()); err != nil {
+		return res, err
+	}
+	if req, err = api.prepare(req, "1004"); err != nil {
+		return res, err
+	}
+	model := "3D"
 	motoInd := 0
-	req.RequestDateTime = &date
-	req.RandomNumber = &rnd
-	req.TxnCode = &code
+	req.PaymentModel = &model
 	req.Transaction.MotoInd = &motoInd
 	return api.Transaction(ctx, req)
 }
 
-func (api *API) Auth3D(ctx context.Context, req *Request) (Response, error) {
-	date := time.Now().Format("2006-01-02T15:04:05.000")
-	rnd := api.Random(128)
-	code := "1000"
+func (api *API) Auth3D(ctx context.Context, req *Request) (res Response, err error) {
+	if err := req.ValidateAt("1000", api.Now()); err != nil {
+		return res, err
+	}
+	if req, err = api.prepare(req, "1000"); err != nil {
+		return res, err
+	}
+	model := "3D"
 	motoInd := 0
-	req.RequestDateTime = &date
-	req.RandomNumber = &rnd
-	req.TxnCode = &code
+	req.PaymentModel = &model
 	req.Transaction.MotoInd = &motoInd
 	return api.Transaction(ctx, req)
 }
 
-func (api *API) PreAuth3Dhtml(ctx context.Context, req *Request) (string, error) {
-	date := time.Now().Format("2006-01-02T15:04:05.000")
-	rnd := api.Random(128)
-	code := "3004"
+func (api *API) PreAuth3Dhtml(ctx context.Context, req *Request) (res string, err error) {
+	if err := req.ValidateAt("3004", api.Now()); err != nil {
+		return res, err
+	}
+	if req, err = api.prepare(req, "3004"); err != nil {
+		return res, err
+	}
 	model := "3D"
 	if req.Order == nil {
 		orderid := uuid.New().String()
 		req.Order = new(Order)
 		req.Order.OrderId = &orderid
 	}
+	if api.SubMerchants != nil {
+		if err := api.SubMerchants.Check(req); err != nil {
+			return res, err
+		}
+	}
 	req.PaymentModel = &model
-	req.RequestDateTime = &date
-	req.RandomNumber = &rnd
-	req.TxnCode = &code
 	payload, _ := QueryString(req)
 	params := []string{"paymentModel", "txnCode", "merchantSafeId", "terminalSafeId", "orderId", "lang", "amount", "ccbRewardAmount", "pcbRewardAmount", "xcbRewardAmount", "currencyCode", "installCount", "okUrl", "failUrl", "emailAddress", "subMerchantId", "creditCard", "expiredDate", "cvv", "randomNumber", "requestDateTime", "b2bIdentityNumber"}
 	hash := api.Hash3D(payload, params)
@@ -555,20 +872,25 @@ func (api *API) PreAuth3Dhtml(ctx context.Context, req *Request) (string, error)
 	return api.Transaction3D(ctx, req)
 }
 
-func (api *API) Auth3Dhtml(ctx context.Context, req *Request) (string, error) {
-	date := time.Now().Format("2006-01-02T15:04:05.000")
-	rnd := api.Random(128)
-	code := "3000"
+func (api *API) Auth3Dhtml(ctx context.Context, req *Request) (res string, err error) {
+	if err := req.ValidateAt("3000", api.Now()); err != nil {
+		return res, err
+	}
+	if req, err = api.prepare(req, "3000"); err != nil {
+		return res, err
+	}
 	model := "3D"
 	if req.Order == nil {
 		orderid := uuid.New().String()
 		req.Order = new(Order)
 		req.Order.OrderId = &orderid
 	}
+	if api.SubMerchants != nil {
+		if err := api.SubMerchants.Check(req); err != nil {
+			return res, err
+		}
+	}
 	req.PaymentModel = &model
-	req.RequestDateTime = &date
-	req.RandomNumber = &rnd
-	req.TxnCode = &code
 	payload, _ := QueryString(req)
 	params := []string{"paymentModel", "txnCode", "merchantSafeId", "terminalSafeId", "orderId", "lang", "amount", "ccbRewardAmount", "pcbRewardAmount", "xcbRewardAmount", "currencyCode", "installCount", "okUrl", "failUrl", "emailAddress", "subMerchantId", "creditCard", "expiredDate", "cvv", "randomNumber", "requestDateTime", "b2bIdentityNumber"}
 	hash := api.Hash3D(payload, params)
@@ -576,67 +898,103 @@ func (api *API) Auth3Dhtml(ctx context.Context, req *Request) (string, error) {
 	return api.Transaction3D(ctx, req)
 }
 
-func (api *API) PostAuth(ctx context.Context, req *Request) (Response, error) {
-	date := time.Now().Format("2006-01-02T15:04:05.000")
-	rnd := api.Random(128)
-	code := "1005"
-	req.RequestDateTime = &date
-	req.RandomNumber = &rnd
-	req.TxnCode = &code
+func (api *API) PostAuth(ctx context.Context, req *Request) (res Response, err error) {
+	if err := req.ValidateAt("1005", api.Now()); err != nil {
+		return res, err
+	}
+	if req, err = api.prepare(req, "1005"); err != nil {
+		return res, err
+	}
 	return api.Transaction(ctx, req)
 }
 
-func (api *API) Refund(ctx context.Context, req *Request) (Response, error) {
-	date := time.Now().Format("2006-01-02T15:04:05.000")
-	rnd := api.Random(128)
-	code := "1002"
-	req.RequestDateTime = &date
-	req.RandomNumber = &rnd
-	req.TxnCode = &code
+func (api *API) Refund(ctx context.Context, req *Request) (res Response, err error) {
+	if err := req.ValidateAt("1002", api.Now()); err != nil {
+		return res, err
+	}
+	if req, err = api.prepare(req, "1002"); err != nil {
+		return res, err
+	}
 	return api.Transaction(ctx, req)
 }
 
-func (api *API) Cancel(ctx context.Context, req *Request) (Response, error) {
-	date := time.Now().Format("2006-01-02T15:04:05.000")
-	rnd := api.Random(128)
-	code := "1003"
-	req.RequestDateTime = &date
-	req.RandomNumber = &rnd
-	req.TxnCode = &code
+func (api *API) Cancel(ctx context.Context, req *Request) (res Response, err error) {
+	if err := req.ValidateAt("1003", api.Now()); err != nil {
+		return res, err
+	}
+	if req, err = api.prepare(req, "1003"); err != nil {
+		return res, err
+	}
 	return api.Transaction(ctx, req)
 }
 
 func (api *API) Transaction(ctx context.Context, req *Request) (res Response, err error) {
+	if req == nil {
+		return res, nilRequest()
+	}
+	if ctx == nil {
+		return res, errors.New("nil context")
+	}
+	req = req.Clone()
+	if api.SubMerchants != nil {
+		if err := api.SubMerchants.Check(req); err != nil {
+			return res, err
+		}
+	}
 	payload, err := json.Marshal(req)
 	if err != nil {
 		return res, err
 	}
-	request, err := http.NewRequestWithContext(ctx, "POST", EndPoints[api.Mode]+"/api/v1/payment/virtualpos/transaction/process", bytes.NewReader(payload))
+	if api.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, api.Timeout)
+		defer cancel()
+	}
+	request, err := http.NewRequestWithContext(ctx, "POST", api.Endpoint()+"/api/v1/payment/virtualpos/transaction/process", bytes.NewReader(payload))
 	if err != nil {
 		return res, err
 	}
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("auth-hash", api.Hash(payload))
-	client := new(http.Client)
+	client := api.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
 	response, err := client.Do(request)
 	if err != nil {
-		return res, err
+		return res, &TransportError{Err: err}
 	}
 	defer response.Body.Close()
-	decoder := json.NewDecoder(response.Body)
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return res, &TransportError{Err: err}
+	}
 	if response.StatusCode == http.StatusOK {
-		if err := decoder.Decode(&res); err == nil {
-			return res, nil
+		if api.VerifyResponse && !api.VerifyHash(response.Header.Get("auth-hash"), body) {
+			return res, ErrResponseSignature
+		}
+		if err := json.Unmarshal(body, &res); err != nil {
+			return res, &GatewayError{StatusCode: response.StatusCode, Message: err.Error(), Body: body}
 		}
 	} else {
-		if err := decoder.Decode(&res.Error); err == nil {
-			return res, errors.New(res.Error.Message)
+		res.Error = new(Error)
+		if err := json.Unmarshal(body, res.Error); err != nil {
+			res.Error = nil
 		}
 	}
-	return res, errors.New("unknown error")
+	if err := responseError(response.StatusCode, body, res, value(req.Lang)); err != nil {
+		return res, err
+	}
+	if api.SubMerchants != nil {
+		api.SubMerchants.record(req)
+	}
+	return res, nil
 }
 
 func (api *API) Transaction3D(ctx context.Context, req *Request) (res string, err error) {
+	if req == nil {
+		return res, nilRequest()
+	}
 	payload, err := QueryString(req)
 	if err != nil {
 		return res, err
@@ -649,7 +1007,7 @@ func (api *API) Transaction3D(ctx context.Context, req *Request) (res string, er
 	html = append(html, `<script type="text/javascript">function submitonload() {document.payment.submit();document.getElementById('button').remove();document.getElementById('body').insertAdjacentHTML("beforeend", "Lütfen bekleyiniz...");}</script>`)
 	html = append(html, `</head>`)
 	html = append(html, `<body onload="javascript:submitonload();" id="body" style="text-align:center;margin:10px;font-family:Arial;font-weight:bold;">`)
-	html = append(html, `<form action="`+EndPoints[api.Mode+"3D"]+`" method="post" name="payment">`)
+	html = append(html, `<form action="`+api.Gateway()+`" method="post" name="payment">`)
 	for k := range payload {
 		html = append(html, `<input type="hidden" name="`+k+`" value="`+payload.Get(k)+`">`)
 	}
@@ -0,0 +1,246 @@
+package akbankpos
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"sync"
+	"testing"
+)
+
+type bank struct {
+	mu       sync.Mutex
+	bodies   [][]byte
+	response func(txncode string) string
+}
+
+func newBank(t *testing.T, response func(txncode string) string, opts ...Option) (*API, *bank) {
+	b := &bank{response: response}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var req Request
+		json.Unmarshal(body, &req)
+		b.mu.Lock()
+		b.bodies = append(b.bodies, body)
+		b.mu.Unlock()
+		w.Write([]byte(b.response(value(req.TxnCode))))
+	}))
+	t.Cleanup(server.Close)
+	return New("merchant", "terminal", "secret", append([]Option{WithBaseURL(server.URL)}, opts...)...), b
+}
+
+func respond(body string) func(string) string {
+	return func(string) string { return body }
+}
+
+func (b *bank) requests() []map[string]interface{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	requests := []map[string]interface{}{}
+	for _, body := range b.bodies {
+		var req map[string]interface{}
+		json.Unmarshal(body, &req)
+		requests = append(requests, req)
+	}
+	return requests
+}
+
+func (b *bank) codes() []string {
+	codes := []string{}
+	for _, req := range b.requests() {
+		code, _ := req["txnCode"].(string)
+		codes = append(codes, code)
+	}
+	return codes
+}
+
+func TestAuth3DhtmlCurrencyCode(t *testing.T) {
+	api := New("merchant", "terminal", "secret")
+	req := api.NewRequest()
+	req.SetCardNumber("4355084355084358")
+	req.SetCardExpiry("12", "99")
+	req.SetCardCode("000")
+	req.SetOrderId("order-1")
+	if err := req.SetAmount("100.00", "TRY"); err != nil {
+		t.Fatal(err)
+	}
+	okurl, failurl := "https://example.com/ok", "https://example.com/fail"
+	req.OkUrl, req.FailUrl = &okurl, &failurl
+	form, err := api.Auth3Dhtml(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := url.Values{}
+	for _, input := range regexp.MustCompile(`name="([^"]+)" value="([^"]*)"`).FindAllStringSubmatch(string(D64(form)), -1) {
+		values.Set(input[1], input[2])
+	}
+	if got := values.Get("currencyCode"); got != "949" {
+		t.Fatalf("currencyCode = %q, want 949", got)
+	}
+	if got := values.Get("amount"); got != "100.00" {
+		t.Fatalf("amount = %q, want 100.00", got)
+	}
+	params := []string{"paymentModel", "txnCode", "merchantSafeId", "terminalSafeId", "orderId", "lang", "amount", "ccbRewardAmount", "pcbRewardAmount", "xcbRewardAmount", "currencyCode", "installCount", "okUrl", "failUrl", "emailAddress", "subMerchantId", "creditCard", "expiredDate", "cvv", "randomNumber", "requestDateTime", "b2bIdentityNumber"}
+	if got, want := values.Get("hash"), api.Hash3D(values, params); got != want {
+		t.Fatalf("hash = %q, want %q", got, want)
+	}
+}
+
+func TestRandomConcurrent(t *testing.T) {
+	api := New("merchant", "terminal", "secret")
+	hex := regexp.MustCompile(`^[0-9A-F]{128}$`)
+	const n = 64
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	seen := map[string]bool{}
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			random, err := api.Random(128)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if !hex.MatchString(random) {
+				t.Errorf("random %q is not 128 hex characters", random)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if seen[random] {
+				t.Errorf("duplicate random %q", random)
+			}
+			seen[random] = true
+		}()
+	}
+	wg.Wait()
+}
+
+func TestRandomSource(t *testing.T) {
+	api := New("merchant", "terminal", "secret", WithRandom(bytes.NewReader([]byte{0x01, 0x23, 0xab, 0xcd, 0xef})))
+	random, err := api.Random(7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if random != "0123ABC" {
+		t.Fatalf("random = %q, want 0123ABC", random)
+	}
+	if _, err := api.Random(128); err == nil {
+		t.Fatal("expected error from exhausted random source")
+	}
+}
+
+func TestWithHTTPClientNil(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"responseCode":"VPS-0000"}`))
+	}))
+	defer server.Close()
+	api := New("merchant", "terminal", "secret", WithBaseURL(server.URL), WithHTTPClient(nil))
+	if api.Client != nil {
+		t.Fatalf("client = %#v, want nil", api.Client)
+	}
+	if _, err := api.QueryOrder(context.Background(), "order-1"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMotoWire(t *testing.T) {
+	api, bank := newBank(t, respond(`{"responseCode":"VPS-0000"}`))
+	ip, model, secureid := "10.0.0.1", "3D", "secure-id"
+	req := api.NewRequest()
+	req.SetCardNumber("4355084355084358")
+	req.SetCardExpiry("12", "99")
+	req.SetAmount("10", "TRY")
+	req.SetOrderId("order-1")
+	req.Customer = &Customer{IpAddress: &ip}
+	req.PaymentModel = &model
+	req.SecureTransaction = &SecureTransaction{SecureId: &secureid}
+	if _, err := api.AuthMoto(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := api.PreAuthMoto(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+	for i, sent := range bank.requests() {
+		if want := []string{"1000", "1004"}[i]; sent["txnCode"] != want {
+			t.Fatalf("txn code = %v, want %s", sent["txnCode"], want)
+		}
+		if transaction := sent["transaction"].(map[string]interface{}); transaction["motoInd"] != 1.0 {
+			t.Fatalf("%v: motoInd = %v, want 1", sent["txnCode"], transaction["motoInd"])
+		}
+		if customer, _ := sent["customer"].(map[string]interface{}); customer["ipAddress"] != nil {
+			t.Fatalf("%v: ipAddress was sent", sent["txnCode"])
+		}
+		if sent["paymentModel"] != nil || sent["secureTransaction"] != nil {
+			t.Fatalf("%v: 3D fields were sent: %v", sent["txnCode"], sent)
+		}
+	}
+	if req.Customer.IpAddress != &ip || req.PaymentModel != &model || req.SecureTransaction.SecureId != &secureid || req.Transaction.MotoInd != nil || req.TxnCode != nil {
+		t.Fatalf("caller request was modified: %+v", req)
+	}
+}
+
+func TestRequestReuseConcurrent(t *testing.T) {
+	api, bank := newBank(t, respond(`{"responseCode":"VPS-0000"}`))
+	stale := "stale-hash"
+	req := api.NewRequest()
+	req.SetCardNumber("4355084355084358")
+	req.SetCardExpiry("12", "99")
+	req.SetOrderId("order-1")
+	req.SetAmount("10", "TRY")
+	req.Hash = &stale
+	builder := api.NewPreAuth().Card("4355084355084358", "12", "99", "000").Amount("10", "TRY").OrderId("order-2")
+	const n = 16
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			if _, err := api.Auth(context.Background(), req); err != nil {
+				t.Error(err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := api.PreAuth(context.Background(), req); err != nil {
+				t.Error(err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := builder.Do(context.Background()); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	count := map[string]int{}
+	randoms := map[string]bool{}
+	for _, sent := range bank.requests() {
+		order, _ := sent["order"].(map[string]interface{})
+		code, _ := sent["txnCode"].(string)
+		count[code+" "+order["orderId"].(string)]++
+		if _, ok := sent["Hash"]; ok {
+			t.Fatalf("hash was left in the body: %v", sent)
+		}
+		random, _ := sent["randomNumber"].(string)
+		if random == "" || randoms[random] {
+			t.Fatalf("random number %q was reused", random)
+		}
+		randoms[random] = true
+	}
+	if count["1000 order-1"] != n || count["1004 order-1"] != n || count["1004 order-2"] != n {
+		t.Fatalf("requests = %v", count)
+	}
+	if req.TxnCode != nil || req.RandomNumber != nil || req.Hash != &stale {
+		t.Fatalf("caller request was modified: %+v", req)
+	}
+	if built, _ := builder.Request(); built.TxnCode != nil || built.RandomNumber != nil || built.Hash != nil {
+		t.Fatalf("builder request was modified: %+v", built)
+	}
+}
@@ -0,0 +1,118 @@
+package akbankpos
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// CommercialCardTypes are matched against the cardType of installment conditions.
+// The values are not confirmed by the bank's documentation; extend the list with what your terminal returns.
+var CommercialCardTypes = []string{"COMMERCIAL", "BUSINESS", "CORPORATE", "TICARI"}
+
+var ErrCardTypeUnknown = errors.New("card type not returned for bin")
+
+func ValidTCKN(id string) bool {
+	if len(id) != 11 || !digits(id) || id[0] == '0' {
+		return false
+	}
+	d := make([]int, 11)
+	for i := range id {
+		d[i] = int(id[i] - '0')
+	}
+	odd := d[0] + d[2] + d[4] + d[6] + d[8]
+	even := d[1] + d[3] + d[5] + d[7]
+	if ((odd*7-even)%10+10)%10 != d[9] {
+		return false
+	}
+	sum := 0
+	for _, n := range d[:10] {
+		sum += n
+	}
+	return sum%10 == d[10]
+}
+
+func ValidVKN(id string) bool {
+	if len(id) != 10 || !digits(id) {
+		return false
+	}
+	sum := 0
+	for i := 0; i < 9; i++ {
+		v1 := (int(id[i]-'0') + 9 - i) % 10
+		v2 := (v1 << (9 - i)) % 9
+		if v1 != 0 && v2 == 0 {
+			v2 = 9
+		}
+		sum += v2
+	}
+	return (10-sum%10)%10 == int(id[9]-'0')
+}
+
+func ValidIdentityNumber(id string) bool {
+	switch len(id) {
+	case 10:
+		return ValidVKN(id)
+	case 11:
+		return ValidTCKN(id)
+	}
+	return false
+}
+
+func (req *Request) SetB2BIdentityNumber(identitynumber string) {
+	if req.B2B == nil {
+		req.B2B = new(B2B)
+	}
+	req.B2B.IdentityNumber = &identitynumber
+}
+
+func commercial(plan InstallmentPlan) (bool, bool) {
+	known := false
+	for _, option := range plan.Options {
+		if option.CardType == "" {
+			continue
+		}
+		known = true
+		for _, cardtype := range CommercialCardTypes {
+			if strings.Contains(strings.ToUpper(option.CardType), cardtype) {
+				return true, true
+			}
+		}
+	}
+	return false, known
+}
+
+func (api *API) IsCommercialBin(ctx context.Context, bin, amount, currency string) (bool, error) {
+	plan, err := api.InstallmentOptions(ctx, bin, amount, currency)
+	if err != nil {
+		return false, err
+	}
+	iscommercial, known := commercial(plan)
+	if !known {
+		return false, ErrCardTypeUnknown
+	}
+	return iscommercial, nil
+}
+
+func validateB2B(req *Request) error {
+	if req == nil {
+		return nilRequest()
+	}
+	if req.B2B == nil || !ValidIdentityNumber(value(req.B2B.IdentityNumber)) {
+		return &ValidationError{Errors: []Errors{{Code: "b2bIdentityNumber", Message: "identity number must be a valid 10 digit VKN or 11 digit TCKN"}}}
+	}
+	return nil
+}
+
+func (api *API) AuthB2B(ctx context.Context, req *Request) (res Response, err error) {
+	if err := validateB2B(req); err != nil {
+		return res, err
+	}
+	return api.Auth(ctx, req)
+}
+
+func (api *API) PreAuthB2B(ctx context.Context, req *Request) (res Response, err error) {
+	if err := validateB2B(req); err != nil {
+		return res, err
+	}
+	return api.PreAuth(ctx, req)
+}
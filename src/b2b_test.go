@@ -0,0 +1,105 @@
+package akbankpos
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAuthB2BSingleRequest(t *testing.T) {
+	codes := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var req Request
+		json.Unmarshal(body, &req)
+		codes = append(codes, value(req.TxnCode))
+		w.Write([]byte(`{"responseCode":"VPS-0000"}`))
+	}))
+	defer server.Close()
+	api := New("merchant", "terminal", "secret", WithBaseURL(server.URL))
+	req := api.NewRequest()
+	req.SetCardNumber("4355084355084358")
+	req.SetCardExpiry("12", "99")
+	req.SetAmount("10", "TRY")
+	req.SetB2BIdentityNumber("0150015264")
+	if _, err := api.AuthB2B(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+	if len(codes) != 1 || codes[0] != "1000" {
+		t.Fatalf("txn codes = %v, want [1000]", codes)
+	}
+}
+
+func TestIsCommercialBin(t *testing.T) {
+	body := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+	api := New("merchant", "terminal", "secret", WithBaseURL(server.URL))
+	for _, test := range []struct {
+		body string
+		want bool
+		err  error
+	}{
+		{`{"responseCode":"VPS-0000","installmentConditionList":[{"installmentCount":1,"cardType":"COMMERCIAL"}]}`, true, nil},
+		{`{"responseCode":"VPS-0000","installmentConditionList":[{"installmentCount":1,"cardType":"CREDIT"}]}`, false, nil},
+		{`{"responseCode":"VPS-0000","installmentConditionList":[{"installmentCount":1}]}`, false, ErrCardTypeUnknown},
+	} {
+		body = test.body
+		got, err := api.IsCommercialBin(context.Background(), "43550843", "10", "TRY")
+		if got != test.want || !errors.Is(err, test.err) {
+			t.Fatalf("%s: got %v, %v", test.body, got, err)
+		}
+	}
+	body = `{"responseCode":"VPS-1999","hostResponseCode":"96"}`
+	if _, err := api.IsCommercialBin(context.Background(), "43550843", "10", "TRY"); err == nil {
+		t.Fatal("query error was ignored")
+	}
+}
+
+func TestValidIdentityNumber(t *testing.T) {
+	for id, want := range map[string]bool{
+		"10000000146": true,
+		"10000000147": false,
+		"10000000156": false,
+		"01000000146": false,
+		"0150015264":  true,
+		"0150015265":  false,
+		"015001526a":  false,
+		"123":         false,
+	} {
+		if got := ValidIdentityNumber(id); got != want {
+			t.Errorf("ValidIdentityNumber(%q) = %v, want %v", id, got, want)
+		}
+	}
+	if ValidTCKN("0150015264") || ValidVKN("10000000146") {
+		t.Error("identity number accepted with the wrong length")
+	}
+}
+
+func TestAuth3DhtmlB2B(t *testing.T) {
+	api := New("merchant", "terminal", "secret")
+	req := api.NewRequest()
+	req.SetCardNumber("4355084355084358")
+	req.SetCardExpiry("12", "99")
+	req.SetAmount("10", "TRY")
+	okurl, failurl := "https://example.com/ok", "https://example.com/fail"
+	req.OkUrl, req.FailUrl = &okurl, &failurl
+	req.SetB2BIdentityNumber("10000000147")
+	var verr *ValidationError
+	if _, err := api.Auth3Dhtml(context.Background(), req); !errors.As(err, &verr) {
+		t.Fatalf("Auth3Dhtml err = %v, want validation error", err)
+	}
+	if _, err := api.PreAuth3Dhtml(context.Background(), req); !errors.As(err, &verr) {
+		t.Fatalf("PreAuth3Dhtml err = %v, want validation error", err)
+	}
+	req.SetB2BIdentityNumber("10000000146")
+	if _, err := api.Auth3Dhtml(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,193 @@
+package akbankpos
+
+import (
+	"context"
+)
+
+type Balance struct {
+	OrderId          string
+	PreAuth          bool
+	Cancelled        bool
+	Currency         Currency
+	Original         Money
+	Captured         Money
+	PartialCancelled Money
+	Refunded         Money
+	Refundable       Money
+	Capturable       Money
+}
+
+func balance(orderid string, res Response) (b Balance, err error) {
+	b.OrderId = orderid
+	var original *TxnDetail
+	var refunded, captured, cancelled Money
+	for _, txn := range res.TxnDetailList {
+		if txn == nil || value(txn.ResponseCode) != "VPS-0000" {
+			continue
+		}
+		switch value(txn.TxnCode) {
+		case "1000", "1004":
+			if original == nil {
+				original = txn
+			}
+		case "1002":
+			refunded, err = refunded.Add(money(txn.Amount))
+		case "1003":
+			b.Cancelled = true
+		case "1005":
+			captured, err = captured.Add(money(txn.Amount))
+		case "1006":
+			cancelled, err = cancelled.Add(money(txn.Amount))
+		}
+		if err != nil {
+			return b, err
+		}
+	}
+	if original == nil {
+		return b, &ValidationError{Errors: []Errors{{Code: "orderId", Message: "no approved sale or pre-authorization found for order"}}}
+	}
+	b.PreAuth = value(original.TxnCode) == "1004"
+	if original.Currency != nil {
+		b.Currency = *original.Currency
+	} else if code, err := LookupCurrency(money(original.Amount).Currency); err == nil {
+		b.Currency = code
+	}
+	if original.PreAuthCloseAmount != nil {
+		captured = *original.PreAuthCloseAmount
+	}
+	if original.PreAuthPartialCancelAmount != nil {
+		cancelled = *original.PreAuthPartialCancelAmount
+	}
+	currency := b.Currency.Alpha()
+	b.Original = NewMoney(money(original.Amount).Minor, currency)
+	b.Refunded = NewMoney(refunded.Minor, currency)
+	b.Captured = NewMoney(0, currency)
+	b.PartialCancelled = NewMoney(0, currency)
+	b.Refundable = NewMoney(0, currency)
+	b.Capturable = NewMoney(0, currency)
+	if b.Cancelled {
+		return b, nil
+	}
+	if b.PreAuth {
+		b.Captured.Minor = captured.Minor
+		b.PartialCancelled.Minor = cancelled.Minor
+		if captured.IsZero() && original.PreAuth() != PreAuthStatusClosed {
+			b.Capturable.Minor = b.Original.Minor - cancelled.Minor
+		}
+		b.Refundable.Minor = b.Captured.Minor - refunded.Minor
+	} else {
+		b.Refundable.Minor = b.Original.Minor - refunded.Minor
+	}
+	return b, nil
+}
+
+func (api *API) Balance(ctx context.Context, orderid string) (Balance, error) {
+	res, err := api.QueryOrder(ctx, orderid)
+	if err != nil {
+		return Balance{OrderId: orderid}, err
+	}
+	return balance(orderid, res)
+}
+
+func partial(req *Request) (orderid string, amount Money, err error) {
+	if req == nil {
+		return "", amount, nilRequest()
+	}
+	if req.Order == nil || value(req.Order.OrderId) == "" {
+		return "", amount, &ValidationError{Errors: []Errors{{Code: "orderId", Message: "order id is required"}}}
+	}
+	if req.Transaction == nil || money(req.Transaction.Amount).Minor <= 0 {
+		return "", amount, &ValidationError{Errors: []Errors{{Code: "amount", Message: "amount must be positive"}}}
+	}
+	return *req.Order.OrderId, *req.Transaction.Amount, nil
+}
+
+func (b Balance) check(req *Request, amount, limit Money, message string) error {
+	// an order without a currency code cannot be compared; the bank checks it instead
+	if b.Currency.Valid() && (req.Transaction.Currency == nil || *req.Transaction.Currency != b.Currency) {
+		return &ValidationError{Errors: []Errors{{Code: "currencyCode", Message: "currency does not match the original transaction (" + b.Currency.String() + ")"}}}
+	}
+	if cmp, err := amount.Cmp(limit); err != nil {
+		return &ValidationError{Errors: []Errors{{Code: "currencyCode", Message: err.Error()}}}
+	} else if cmp > 0 {
+		return &ValidationError{Errors: []Errors{{Code: "amount", Message: message}}}
+	}
+	return nil
+}
+
+func (api *API) RefundPartial(ctx context.Context, req *Request) (res Response, b Balance, err error) {
+	orderid, amount, err := partial(req)
+	if err != nil {
+		return res, b, err
+	}
+	if b, err = api.Balance(ctx, orderid); err != nil {
+		return res, b, err
+	}
+	if err = b.check(req, amount, b.Refundable, "amount exceeds refundable balance"); err != nil {
+		return res, b, err
+	}
+	if res, err = api.Refund(ctx, req); err != nil {
+		return res, b, err
+	}
+	b.Refunded.Minor += amount.Minor
+	b.Refundable.Minor -= amount.Minor
+	return res, b, nil
+}
+
+func (api *API) CapturePartial(ctx context.Context, req *Request) (res Response, b Balance, err error) {
+	orderid, amount, err := partial(req)
+	if err != nil {
+		return res, b, err
+	}
+	if b, err = api.Balance(ctx, orderid); err != nil {
+		return res, b, err
+	}
+	if !b.PreAuth {
+		return res, b, &ValidationError{Errors: []Errors{{Code: "orderId", Message: "order is not a pre-authorization"}}}
+	}
+	if err = b.check(req, amount, b.Capturable, "amount exceeds capturable balance"); err != nil {
+		return res, b, err
+	}
+	if res, err = api.PostAuth(ctx, req); err != nil {
+		return res, b, err
+	}
+	b.Captured = NewMoney(amount.Minor, b.Captured.Currency)
+	b.Capturable = NewMoney(0, b.Capturable.Currency)
+	b.Refundable.Minor = b.Captured.Minor - b.Refunded.Minor
+	return res, b, nil
+}
+
+func (api *API) PreAuthPartialCancel(ctx context.Context, orderid, amount string) (res Response, b Balance, err error) {
+	if orderid == "" {
+		return res, b, &ValidationError{Errors: []Errors{{Code: "orderId", Message: "order id is required"}}}
+	}
+	if b, err = api.Balance(ctx, orderid); err != nil {
+		return res, b, err
+	}
+	if !b.PreAuth || b.Cancelled || b.Capturable.Minor <= 0 {
+		return res, b, &ValidationError{Errors: []Errors{{Code: "orderId", Message: "order has no open pre-authorization"}}}
+	}
+	release, err := ParseMoney(amount, b.Capturable.Currency)
+	if err != nil || release.Minor <= 0 {
+		return res, b, &ValidationError{Errors: []Errors{{Code: "amount", Message: "amount must be positive"}}}
+	}
+	if release.Minor >= b.Capturable.Minor {
+		return res, b, &ValidationError{Errors: []Errors{{Code: "amount", Message: "amount must be less than the open pre-authorization; use Cancel to release it fully"}}}
+	}
+	req := api.NewRequest()
+	req.SetOrderId(orderid)
+	req.Transaction = new(Transaction)
+	req.Transaction.Amount = &release
+	if b.Currency.Valid() {
+		req.Transaction.Currency = &b.Currency
+	}
+	if req, err = api.prepare(req, "1006"); err != nil {
+		return res, b, err
+	}
+	if res, err = api.Transaction(ctx, req); err != nil {
+		return res, b, err
+	}
+	b.PartialCancelled.Minor += release.Minor
+	b.Capturable.Minor -= release.Minor
+	return res, b, nil
+}
@@ -0,0 +1,126 @@
+package akbankpos
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRefundPartialCurrencyMismatch(t *testing.T) {
+	refunds := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var req Request
+		json.Unmarshal(body, &req)
+		if value(req.TxnCode) == "1002" {
+			refunds++
+		}
+		w.Write([]byte(`{"responseCode":"VPS-0000","txnDetailList":[{"txnCode":"1000","responseCode":"VPS-0000","orderId":"order-1","amount":100.00,"currencyCode":949}]}`))
+	}))
+	defer server.Close()
+	api := New("merchant", "terminal", "secret", WithBaseURL(server.URL))
+	for _, currency := range []string{"USD", "JPY"} {
+		req := api.NewRequest()
+		req.SetOrderId("order-1")
+		if err := req.SetAmount("1", currency); err != nil {
+			t.Fatal(err)
+		}
+		_, _, err := api.RefundPartial(context.Background(), req)
+		var verr *ValidationError
+		if !errors.As(err, &verr) {
+			t.Fatalf("%s: err = %v, want validation error", currency, err)
+		}
+	}
+	if refunds != 0 {
+		t.Fatalf("sent %d refunds, want 0", refunds)
+	}
+	req := api.NewRequest()
+	req.SetOrderId("order-1")
+	req.SetAmount("50", "TRY")
+	_, b, err := api.RefundPartial(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Refundable.String() != "50.00" || refunds != 1 {
+		t.Fatalf("refundable = %s, refunds = %d", b.Refundable, refunds)
+	}
+}
+
+func TestMoneyCurrencyMismatch(t *testing.T) {
+	try := NewMoney(100, "TRY")
+	usd := NewMoney(100, "USD")
+	var mismatch *CurrencyMismatchError
+	if _, err := try.Add(usd); !errors.As(err, &mismatch) {
+		t.Fatalf("Add err = %v", err)
+	}
+	if _, err := try.Sub(usd); !errors.As(err, &mismatch) {
+		t.Fatalf("Sub err = %v", err)
+	}
+	if _, err := try.Cmp(usd); !errors.As(err, &mismatch) {
+		t.Fatalf("Cmp err = %v", err)
+	}
+	sum, err := Money{}.Add(try)
+	if err != nil || sum != NewMoney(100, "TRY") {
+		t.Fatalf("sum = %v, err = %v", sum, err)
+	}
+}
+
+func TestRefundPartialUnknownCurrency(t *testing.T) {
+	api, bank := newBank(t, respond(`{"responseCode":"VPS-0000","txnDetailList":[{"txnCode":"1000","responseCode":"VPS-0000","orderId":"order-1","amount":100.00}]}`))
+	req := api.NewRequest()
+	req.SetOrderId("order-1")
+	req.SetAmount("40", "TRY")
+	_, b, err := api.RefundPartial(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Refundable.String() != "60.00" {
+		t.Fatalf("refundable = %s, want 60.00", b.Refundable)
+	}
+	if codes := bank.codes(); len(codes) != 2 || codes[1] != "1002" {
+		t.Fatalf("txn codes = %v", codes)
+	}
+}
+
+func TestPreAuthPartialCancel(t *testing.T) {
+	order := `{"responseCode":"VPS-0000","txnDetailList":[{"txnCode":"1004","responseCode":"VPS-0000","orderId":"order-1","amount":100.00,"currencyCode":949,"preAuthStatus":"O"}]}`
+	api, bank := newBank(t, func(txncode string) string {
+		if txncode == "1010" {
+			return order
+		}
+		return `{"responseCode":"VPS-0000"}`
+	})
+	var verr *ValidationError
+	if _, _, err := api.PreAuthPartialCancel(context.Background(), "order-1", "100"); !errors.As(err, &verr) {
+		t.Fatalf("full release err = %v, want validation error", err)
+	}
+	_, b, err := api.PreAuthPartialCancel(context.Background(), "order-1", "30")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Capturable.String() != "70.00" || b.PartialCancelled.String() != "30.00" {
+		t.Fatalf("capturable = %s, partial cancelled = %s", b.Capturable, b.PartialCancelled)
+	}
+	requests := bank.requests()
+	if codes := bank.codes(); len(codes) != 3 || codes[0] != "1010" || codes[1] != "1010" || codes[2] != "1006" {
+		t.Fatalf("txn codes = %v, want [1010 1010 1006]", codes)
+	}
+	if transaction := requests[2]["transaction"].(map[string]interface{}); transaction["amount"] != 30.0 || transaction["currencyCode"] != 949.0 {
+		t.Fatalf("transaction = %v", transaction)
+	}
+	order = `{"responseCode":"VPS-0000","txnDetailList":[{"txnCode":"1000","responseCode":"VPS-0000","orderId":"order-1","amount":100.00,"currencyCode":949}]}`
+	if _, _, err := api.PreAuthPartialCancel(context.Background(), "order-1", "30"); !errors.As(err, &verr) {
+		t.Fatalf("sale err = %v, want validation error", err)
+	}
+	order = `{"responseCode":"VPS-0000","txnDetailList":[{"txnCode":"1004","responseCode":"VPS-0000","orderId":"order-1","amount":100.00,"currencyCode":949,"preAuthStatus":"C"}]}`
+	if _, _, err := api.PreAuthPartialCancel(context.Background(), "order-1", "30"); !errors.As(err, &verr) {
+		t.Fatalf("closed pre-auth err = %v, want validation error", err)
+	}
+	if codes := bank.codes(); len(codes) != 5 || codes[4] != "1010" {
+		t.Fatalf("txn codes = %v, want no further 1006", codes)
+	}
+}
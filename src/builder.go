@@ -0,0 +1,105 @@
+package akbankpos
+
+import "context"
+
+type Builder struct {
+	req *Request
+	op  func(context.Context, *Request) (Response, error)
+	err error
+}
+
+func (api *API) build(op func(context.Context, *Request) (Response, error)) *Builder {
+	return &Builder{req: api.NewRequest(), op: op}
+}
+
+func (api *API) NewSale() *Builder {
+	return api.build(api.Auth)
+}
+
+func (api *API) NewPreAuth() *Builder {
+	return api.build(api.PreAuth)
+}
+
+func (api *API) NewSaleMoto() *Builder {
+	return api.build(api.AuthMoto)
+}
+
+func (api *API) NewPreAuthMoto() *Builder {
+	return api.build(api.PreAuthMoto)
+}
+
+func (api *API) NewPostAuth() *Builder {
+	return api.build(api.PostAuth)
+}
+
+func (api *API) NewRefund() *Builder {
+	return api.build(api.Refund)
+}
+
+func (api *API) NewCancel() *Builder {
+	return api.build(api.Cancel)
+}
+
+func (b *Builder) fail(err error) *Builder {
+	if b.err == nil {
+		b.err = err
+	}
+	return b
+}
+
+func (b *Builder) Card(cardnumber, cardmonth, cardyear, cardcode string) *Builder {
+	b.req.SetCardNumber(cardnumber)
+	b.req.SetCardExpiry(cardmonth, cardyear)
+	b.req.SetCardCode(cardcode)
+	return b
+}
+
+func (b *Builder) Amount(price, currency string) *Builder {
+	if err := b.req.SetAmount(price, currency); err != nil {
+		return b.fail(err)
+	}
+	return b
+}
+
+func (b *Builder) Installment(installment string) *Builder {
+	if err := b.req.SetInstallment(installment); err != nil {
+		return b.fail(&ValidationError{Errors: []Errors{{Code: "installCount", Message: err.Error()}}})
+	}
+	return b
+}
+
+func (b *Builder) OrderId(orderid string) *Builder {
+	b.req.SetOrderId(orderid)
+	return b
+}
+
+func (b *Builder) Lang(lang string) *Builder {
+	b.req.SetLang(lang)
+	return b
+}
+
+func (b *Builder) CustomerIPv4(ipaddress string) *Builder {
+	b.req.SetCustomerIPv4(ipaddress)
+	return b
+}
+
+func (b *Builder) CustomerEmail(email string) *Builder {
+	b.req.SetCustomerEmail(email)
+	return b
+}
+
+func (b *Builder) SubMerchant(submerchantid string) *Builder {
+	b.req.SetSubMerchant(submerchantid)
+	return b
+}
+
+func (b *Builder) Request() (*Request, error) {
+	return b.req.Clone(), b.err
+}
+
+func (b *Builder) Do(ctx context.Context) (res Response, err error) {
+	if b.err != nil {
+		return res, b.err
+	}
+	return b.op(ctx, b.req)
+}
@@ -0,0 +1,192 @@
+package akbankpos
+
+import (
+	"context"
+	"crypto/hmac"
+	"errors"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var ErrCallbackHash = errors.New("invalid callback hash")
+
+type Callback struct {
+	TxnCode          *string `json:"txnCode,omitempty" form:"txnCode,omitempty"`
+	ResponseCode     *string `json:"responseCode,omitempty" form:"responseCode,omitempty"`
+	ResponseMessage  *string `json:"responseMessage,omitempty" form:"responseMessage,omitempty"`
+	HostResponseCode *string `json:"hostResponseCode,omitempty" form:"hostResponseCode,omitempty"`
+	HostMessage      *string `json:"hostMessage,omitempty" form:"hostMessage,omitempty"`
+	PaymentModel     *string `json:"paymentModel,omitempty" form:"paymentModel,omitempty"`
+	MerchantSafeId   *string `json:"merchantSafeId,omitempty" form:"merchantSafeId,omitempty"`
+	TerminalSafeId   *string `json:"terminalSafeId,omitempty" form:"terminalSafeId,omitempty"`
+	OrderId          *string `json:"orderId,omitempty" form:"orderId,omitempty"`
+	Amount           *string `json:"amount,omitempty" form:"amount,omitempty"`
+	Currency         *string `json:"currencyCode,omitempty" form:"currencyCode,omitempty"`
+	Installment      *string `json:"installCount,omitempty" form:"installCount,omitempty"`
+	SecureId         *string `json:"secureId,omitempty" form:"secureId,omitempty"`
+	SecureEcomInd    *string `json:"secureEcomInd,omitempty" form:"secureEcomInd,omitempty"`
+	SecureData       *string `json:"secureData,omitempty" form:"secureData,omitempty"`
+	SecureMd         *string `json:"secureMd,omitempty" form:"secureMd,omitempty"`
+	MdStatus         *string `json:"mdStatus,omitempty" form:"mdStatus,omitempty"`
+	MdErrorMessage   *string `json:"mdErrorMessage,omitempty" form:"mdErrorMessage,omitempty"`
+	RandomNumber     *string `json:"randomNumber,omitempty" form:"randomNumber,omitempty"`
+	RequestDateTime  *string `json:"requestDateTime,omitempty" form:"requestDateTime,omitempty"`
+	HashParams       *string `json:"hashParams,omitempty" form:"hashParams,omitempty"`
+	Hash             *string `json:"hash,omitempty" form:"hash,omitempty"`
+}
+
+func ParseForm(values url.Values, v interface{}) error {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return errors.New("invalid form target")
+	}
+	unreflector(values, value.Elem())
+	return nil
+}
+
+func unreflector(values url.Values, val reflect.Value) {
+	typ := val.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		sv := val.Field(i)
+		n, ok := sf.Tag.Lookup("form")
+		if !ok {
+			continue
+		}
+		name := strings.Split(n, ",")[0]
+		if _, ok := values[name]; !ok {
+			continue
+		}
+		if sv.Kind() == reflect.Ptr && sf.Type.Elem().Kind() == reflect.String {
+			value := values.Get(name)
+			sv.Set(reflect.ValueOf(&value))
+		}
+	}
+}
+
+func (api *API) Verify3D(values url.Values) bool {
+	hash := values.Get("hash")
+	params := HashParams(values.Get("hashParams"))
+	if hash == "" || len(params) == 0 {
+		return false
+	}
+	return hmac.Equal([]byte(api.Hash3D(values, params)), []byte(hash))
+}
+
+func (api *API) Parse3DCallback(r *http.Request) (*Callback, error) {
+	if r == nil {
+		return nil, errors.New("missing callback request")
+	}
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+	if !api.Verify3D(r.PostForm) {
+		return nil, ErrCallbackHash
+	}
+	// only fields covered by the hash are trusted; anything else could be forged
+	covered := url.Values{"hash": r.PostForm["hash"], "hashParams": r.PostForm["hashParams"]}
+	for _, param := range HashParams(r.PostForm.Get("hashParams")) {
+		if v, ok := r.PostForm[param]; ok {
+			covered[param] = v
+		}
+	}
+	callback := new(Callback)
+	if err := ParseForm(covered, callback); err != nil {
+		return nil, err
+	}
+	return callback, nil
+}
+
+func (cb *Callback) Authenticated() bool {
+	if cb.MdStatus == nil {
+		return false
+	}
+	switch *cb.MdStatus {
+	case "1", "2", "3", "4":
+		return true
+	}
+	return false
+}
+
+func (api *API) Complete3D(ctx context.Context, cb *Callback, req *Request) (res Response, err error) {
+	if req == nil {
+		return res, nilRequest()
+	}
+	if cb == nil {
+		return res, errors.New("missing callback")
+	}
+	if !cb.Authenticated() {
+		if cb.MdErrorMessage != nil && *cb.MdErrorMessage != "" {
+			return res, errors.New(*cb.MdErrorMessage)
+		}
+		return res, errors.New("3d authentication failed")
+	}
+	if cb.PaymentModel != nil && *cb.PaymentModel != "" && *cb.PaymentModel != "3D" {
+		return res, errors.New("payment model " + *cb.PaymentModel + " is completed by the bank")
+	}
+	if cb.OrderId == nil || *cb.OrderId == "" {
+		return res, errors.New("missing callback order id")
+	}
+	if value(cb.SecureId) == "" || value(cb.SecureData) == "" || value(cb.SecureMd) == "" {
+		return res, errors.New("missing callback secure data")
+	}
+	req = req.Clone()
+	code := ""
+	if cb.TxnCode != nil {
+		code = *cb.TxnCode
+	}
+	if code != "3000" && code != "3004" {
+		return res, errors.New("unsupported 3d txn code: " + code)
+	}
+	if req.Order == nil {
+		req.Order = new(Order)
+	}
+	if orderid := value(req.Order.OrderId); orderid != "" && orderid != *cb.OrderId {
+		return res, errors.New("callback order id " + *cb.OrderId + " does not match request order id " + orderid)
+	}
+	req.Order.OrderId = cb.OrderId
+	if req.Transaction == nil {
+		req.Transaction = new(Transaction)
+	}
+	if cb.Currency != nil {
+		currency, err := LookupCurrency(*cb.Currency)
+		if err != nil {
+			return res, errors.New("invalid callback currency: " + *cb.Currency)
+		}
+		if req.Transaction.Currency != nil && *req.Transaction.Currency != currency {
+			return res, errors.New("callback currency " + currency.String() + " does not match request currency " + req.Transaction.Currency.String())
+		}
+		req.Transaction.Currency = &currency
+	}
+	if cb.Amount != nil {
+		alpha := ""
+		if req.Transaction.Currency != nil {
+			alpha = req.Transaction.Currency.Alpha()
+		}
+		amount, err := ParseMoney(*cb.Amount, alpha)
+		if err != nil {
+			return res, errors.New("invalid callback amount: " + *cb.Amount)
+		}
+		if req.Transaction.Amount != nil && req.Transaction.Amount.Minor != amount.Minor {
+			return res, errors.New("callback amount " + amount.String() + " does not match request amount " + req.Transaction.Amount.String())
+		}
+		req.Transaction.Amount = &amount
+	}
+	if req.Transaction.Installment == nil && cb.Installment != nil {
+		if parse, err := strconv.Atoi(*cb.Installment); err == nil {
+			req.Transaction.Installment = &parse
+		}
+	}
+	req.SecureTransaction = new(SecureTransaction)
+	req.SecureTransaction.SecureId = cb.SecureId
+	req.SecureTransaction.SecureEcomInd = cb.SecureEcomInd
+	req.SecureTransaction.SecureData = cb.SecureData
+	req.SecureTransaction.SecureMd = cb.SecureMd
+	if code == "3004" {
+		return api.PreAuth3D(ctx, req)
+	}
+	return api.Auth3D(ctx, req)
+}
@@ -0,0 +1,127 @@
+package akbankpos
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func post(api *API, values url.Values, params ...string) *http.Request {
+	values.Set("hashParams", strings.Join(params, "+"))
+	values.Set("hash", api.Hash3D(values, params))
+	r := httptest.NewRequest("POST", "/ok", strings.NewReader(values.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return r
+}
+
+func TestParse3DCallbackUncoveredFields(t *testing.T) {
+	api := New("merchant", "terminal", "secret")
+	values := url.Values{
+		"orderId":      {"order-1"},
+		"txnCode":      {"3000"},
+		"mdStatus":     {"1"},
+		"responseCode": {"VPS-0000"},
+		"secureId":     {"id"},
+		"secureData":   {"data"},
+		"secureMd":     {"md"},
+	}
+	callback, err := api.Parse3DCallback(post(api, values, "orderId", "txnCode"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if callback.MdStatus != nil || callback.SecureId != nil || callback.ResponseCode != nil {
+		t.Fatalf("unsigned fields were populated: %+v", callback)
+	}
+	if value(callback.OrderId) != "order-1" {
+		t.Fatalf("order id = %q", value(callback.OrderId))
+	}
+	if _, err := api.Complete3D(context.Background(), callback, api.NewRequest()); err == nil {
+		t.Fatal("completed a callback whose mdStatus was not signed")
+	}
+	callback, err = api.Parse3DCallback(post(api, values, "orderId", "txnCode", "mdStatus", "responseCode", "secureId", "secureData", "secureMd"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !callback.Authenticated() || value(callback.SecureMd) != "md" {
+		t.Fatalf("signed fields were dropped: %+v", callback)
+	}
+}
+
+func TestParse3DCallbackTampered(t *testing.T) {
+	api := New("merchant", "terminal", "secret")
+	r := post(api, url.Values{"orderId": {"order-1"}, "mdStatus": {"0"}}, "orderId", "mdStatus")
+	r.ParseForm()
+	r.PostForm.Set("mdStatus", "1")
+	if _, err := api.Parse3DCallback(r); err != ErrCallbackHash {
+		t.Fatalf("err = %v, want %v", err, ErrCallbackHash)
+	}
+}
+
+func authenticated(txncode, orderid, amount string) *Callback {
+	s := func(v string) *string { return &v }
+	return &Callback{
+		TxnCode:       s(txncode),
+		MdStatus:      s("1"),
+		PaymentModel:  s("3D"),
+		OrderId:       s(orderid),
+		Amount:        s(amount),
+		Currency:      s("949"),
+		SecureId:      s("secure-id"),
+		SecureEcomInd: s("05"),
+		SecureData:    s("secure-data"),
+		SecureMd:      s("secure-md"),
+	}
+}
+
+func TestComplete3D(t *testing.T) {
+	api, bank := newBank(t, respond(`{"responseCode":"VPS-0000"}`))
+	for i, test := range []struct{ callback, sent string }{{"3000", "1000"}, {"3004", "1004"}} {
+		req := api.NewRequest()
+		req.SetOrderId("order-1")
+		req.SetAmount("10.00", "TRY")
+		if _, err := api.Complete3D(context.Background(), authenticated(test.callback, "order-1", "10.00"), req); err != nil {
+			t.Fatal(err)
+		}
+		sent := bank.requests()[i]
+		if sent["txnCode"] != test.sent || sent["paymentModel"] != "3D" {
+			t.Fatalf("%s: sent %v", test.callback, sent)
+		}
+		secure, _ := sent["secureTransaction"].(map[string]interface{})
+		if secure["secureId"] != "secure-id" || secure["secureEcomInd"] != "05" || secure["secureData"] != "secure-data" || secure["secureMd"] != "secure-md" {
+			t.Fatalf("%s: secure transaction %v", test.callback, secure)
+		}
+		order, _ := sent["order"].(map[string]interface{})
+		transaction, _ := sent["transaction"].(map[string]interface{})
+		if order["orderId"] != "order-1" || transaction["amount"] != 10.0 || transaction["currencyCode"] != 949.0 {
+			t.Fatalf("%s: order %v transaction %v", test.callback, order, transaction)
+		}
+	}
+	req := api.NewRequest()
+	if _, err := api.Complete3D(context.Background(), authenticated("3000", "order-2", "5.00"), req); err != nil {
+		t.Fatal(err)
+	}
+	if transaction := bank.requests()[2]["transaction"].(map[string]interface{}); transaction["amount"] != 5.0 {
+		t.Fatalf("amount from callback was not used: %v", transaction)
+	}
+}
+
+func TestComplete3DMismatch(t *testing.T) {
+	api, bank := newBank(t, respond(`{"responseCode":"VPS-0000"}`))
+	for name, prepare := range map[string]func(*Request){
+		"order id": func(req *Request) { req.SetOrderId("expensive-order"); req.SetAmount("1.00", "TRY") },
+		"amount":   func(req *Request) { req.SetOrderId("cheap-order"); req.SetAmount("1000.00", "TRY") },
+		"currency": func(req *Request) { req.SetOrderId("cheap-order"); req.SetAmount("1.00", "USD") },
+	} {
+		req := api.NewRequest()
+		prepare(req)
+		if _, err := api.Complete3D(context.Background(), authenticated("3000", "cheap-order", "1.00"), req); err == nil {
+			t.Fatalf("%s mismatch was accepted", name)
+		}
+	}
+	if len(bank.requests()) != 0 {
+		t.Fatalf("sent %d requests, want 0", len(bank.requests()))
+	}
+}
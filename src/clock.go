@@ -0,0 +1,89 @@
+package akbankpos
+
+import (
+	"errors"
+	"time"
+)
+
+const DateTimeLayout = "2006-01-02T15:04:05.000"
+
+var Istanbul = istanbul()
+
+var layouts = []string{
+	DateTimeLayout,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05.000",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	"20060102150405",
+	"20060102",
+}
+
+type Clock interface {
+	Now() time.Time
+}
+
+type ClockFunc func() time.Time
+
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+func WithClock(clock Clock) Option {
+	return func(api *API) {
+		api.Clock = clock
+	}
+}
+
+func istanbul() *time.Location {
+	if location, err := time.LoadLocation("Europe/Istanbul"); err == nil {
+		return location
+	}
+	return time.FixedZone("+03", 3*60*60)
+}
+
+func (api *API) Now() time.Time {
+	if api.Clock != nil {
+		return api.Clock.Now().In(Istanbul)
+	}
+	return time.Now().In(Istanbul)
+}
+
+func ParseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, errors.New("empty time")
+	}
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t.In(Istanbul), nil
+	}
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, value, Istanbul); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, errors.New("invalid time: " + value)
+}
+
+func (res Response) TxnTime() (time.Time, error) {
+	return ParseTime(value(res.TxnDateTime))
+}
+
+func (res Response) LinkExpireTime() (time.Time, error) {
+	return ParseTime(value(res.LinkExpireDate))
+}
+
+func (txn TxnDetail) TxnTime() (time.Time, error) {
+	return ParseTime(value(txn.TxnDateTime))
+}
+
+func (txn TxnDetail) PlannedTime() (time.Time, error) {
+	return ParseTime(value(txn.PlannedDateTime))
+}
+
+func (detail LinkDetail) ExpireTime() (time.Time, error) {
+	return ParseTime(value(detail.LinkExpireDate))
+}
+
+func (payment RecurringPayment) PlannedTime() (time.Time, error) {
+	return ParseTime(payment.PlannedDateTime)
+}
@@ -0,0 +1,50 @@
+package akbankpos
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRequestDateTimeIstanbul(t *testing.T) {
+	clock := ClockFunc(func() time.Time { return time.Date(2024, 1, 1, 21, 30, 0, 0, time.UTC) })
+	api, bank := newBank(t, respond(`{"responseCode":"VPS-0000"}`), WithClock(clock))
+	if _, err := api.QueryOrder(context.Background(), "order-1"); err != nil {
+		t.Fatal(err)
+	}
+	if got := bank.requests()[0]["requestDateTime"]; got != "2024-01-02T00:30:00.000" {
+		t.Fatalf("requestDateTime = %v, want 2024-01-02T00:30:00.000", got)
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	want := time.Date(2024, 3, 5, 14, 7, 9, 0, Istanbul)
+	for _, value := range []string{
+		"2024-03-05T14:07:09.000",
+		"2024-03-05T14:07:09",
+		"2024-03-05 14:07:09.000",
+		"2024-03-05 14:07:09",
+		"20240305140709",
+		"2024-03-05T11:07:09Z",
+		"2024-03-05T14:07:09+03:00",
+	} {
+		got, err := ParseTime(value)
+		if err != nil {
+			t.Fatalf("%s: %v", value, err)
+		}
+		if !got.Equal(want) || got.Location() != Istanbul {
+			t.Errorf("%s: got %v, want %v", value, got, want)
+		}
+	}
+	day := time.Date(2024, 3, 5, 0, 0, 0, 0, Istanbul)
+	for _, value := range []string{"2024-03-05", "20240305"} {
+		if got, err := ParseTime(value); err != nil || !got.Equal(day) {
+			t.Errorf("%s: got %v, %v", value, got, err)
+		}
+	}
+	for _, value := range []string{"", "05/03/2024"} {
+		if _, err := ParseTime(value); err == nil {
+			t.Errorf("%q: parsed", value)
+		}
+	}
+}
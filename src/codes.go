@@ -0,0 +1,121 @@
+package akbankpos
+
+import "strings"
+
+type Category string
+
+const (
+	CategoryApproved    Category = "approved"
+	CategorySoftDecline Category = "soft_decline"
+	CategoryHardDecline Category = "hard_decline"
+	CategoryFraud       Category = "fraud"
+	CategorySystemError Category = "system_error"
+	CategoryRetryable   Category = "retryable"
+)
+
+type Code struct {
+	Code      string
+	Category  Category
+	Retryable bool
+	TR        string
+	EN        string
+}
+
+// ResponseCodes lists only the gateway codes confirmed here; add entries from the bank's VPS code list
+// before relying on them. Unlisted codes are classified by the host code, or as a non-retryable system error.
+var ResponseCodes = map[string]Code{
+	"VPS-0000": {Category: CategoryApproved, TR: "İşlem onaylandı", EN: "Transaction approved"},
+}
+
+var HostResponseCodes = map[string]Code{
+	"00": {Category: CategoryApproved, TR: "İşlem onaylandı", EN: "Transaction approved"},
+	"01": {Category: CategorySoftDecline, TR: "Kartınızı veren bankayı arayınız", EN: "Please contact your card issuer"},
+	"02": {Category: CategorySoftDecline, TR: "Kartınızı veren bankayı arayınız", EN: "Please contact your card issuer"},
+	"03": {Category: CategorySystemError, TR: "Geçersiz üye işyeri", EN: "Invalid merchant"},
+	"04": {Category: CategoryFraud, TR: "Kartınız ile işlem yapılamıyor", EN: "Your card cannot be used"},
+	"05": {Category: CategorySoftDecline, TR: "İşlem onaylanmadı", EN: "Transaction declined"},
+	"06": {Category: CategoryRetryable, Retryable: true, TR: "İşlem sırasında hata oluştu, tekrar deneyiniz", EN: "An error occurred, please try again"},
+	"07": {Category: CategoryFraud, TR: "Kartınız ile işlem yapılamıyor", EN: "Your card cannot be used"},
+	"08": {Category: CategorySoftDecline, TR: "Kimlik doğrulaması gerekiyor", EN: "Identity verification required"},
+	"11": {Category: CategoryApproved, TR: "İşlem onaylandı", EN: "Transaction approved"},
+	"12": {Category: CategoryHardDecline, TR: "Geçersiz işlem", EN: "Invalid transaction"},
+	"13": {Category: CategoryHardDecline, TR: "Geçersiz tutar", EN: "Invalid amount"},
+	"14": {Category: CategoryHardDecline, TR: "Kart numarası hatalı", EN: "Invalid card number"},
+	"15": {Category: CategoryHardDecline, TR: "Kartı veren banka bulunamadı", EN: "No such card issuer"},
+	"19": {Category: CategoryRetryable, Retryable: true, TR: "İşlemi tekrar deneyiniz", EN: "Please try again"},
+	"30": {Category: CategorySystemError, TR: "Mesaj formatı hatalı", EN: "Message format error"},
+	"33": {Category: CategoryHardDecline, TR: "Kartınızın süresi dolmuş", EN: "Your card has expired"},
+	"34": {Category: CategoryFraud, TR: "Kartınız ile işlem yapılamıyor", EN: "Your card cannot be used"},
+	"36": {Category: CategoryFraud, TR: "Kartınız ile işlem yapılamıyor", EN: "Your card cannot be used"},
+	"37": {Category: CategoryFraud, TR: "Kartınızı veren bankayı arayınız", EN: "Please contact your card issuer"},
+	"38": {Category: CategoryHardDecline, TR: "Şifre deneme sayısı aşıldı", EN: "PIN tries exceeded"},
+	"41": {Category: CategoryFraud, TR: "Kartınız ile işlem yapılamıyor", EN: "Your card cannot be used"},
+	"43": {Category: CategoryFraud, TR: "Kartınız ile işlem yapılamıyor", EN: "Your card cannot be used"},
+	"51": {Category: CategorySoftDecline, TR: "Kart limitiniz yetersiz", EN: "Insufficient funds"},
+	"54": {Category: CategoryHardDecline, TR: "Kartınızın süresi dolmuş", EN: "Your card has expired"},
+	"55": {Category: CategorySoftDecline, TR: "Şifre hatalı", EN: "Incorrect PIN"},
+	"56": {Category: CategoryHardDecline, TR: "Kart kaydı bulunamadı", EN: "No card record"},
+	"57": {Category: CategoryHardDecline, TR: "Kartınız bu işleme kapalı", EN: "Transaction not permitted to cardholder"},
+	"58": {Category: CategorySystemError, TR: "Üye işyeri bu işleme kapalı", EN: "Transaction not permitted to terminal"},
+	"59": {Category: CategoryFraud, TR: "Kartınız ile işlem yapılamıyor", EN: "Your card cannot be used"},
+	"61": {Category: CategorySoftDecline, TR: "İşlem tutarı limiti aşıldı", EN: "Amount limit exceeded"},
+	"62": {Category: CategoryHardDecline, TR: "Kartınız kısıtlı", EN: "Restricted card"},
+	"63": {Category: CategoryFraud, TR: "Kartınız ile işlem yapılamıyor", EN: "Your card cannot be used"},
+	"65": {Category: CategorySoftDecline, TR: "İşlem adedi limiti aşıldı", EN: "Transaction count limit exceeded"},
+	"75": {Category: CategoryHardDecline, TR: "Şifre deneme sayısı aşıldı", EN: "PIN tries exceeded"},
+	"91": {Category: CategoryRetryable, Retryable: true, TR: "Kartı veren bankaya ulaşılamıyor, tekrar deneyiniz", EN: "Card issuer unavailable, please try again"},
+	"92": {Category: CategoryRetryable, Retryable: true, TR: "Kartı veren bankaya ulaşılamıyor, tekrar deneyiniz", EN: "Card issuer unavailable, please try again"},
+	"94": {Category: CategoryHardDecline, TR: "Mükerrer işlem", EN: "Duplicate transaction"},
+	"96": {Category: CategoryRetryable, Retryable: true, TR: "Sistem hatası, tekrar deneyiniz", EN: "System error, please try again"},
+}
+
+var unknownCode = Code{Category: CategorySystemError, TR: "İşlem onaylanmadı", EN: "Transaction declined"}
+
+func LookupCode(responsecode, hostresponsecode string) Code {
+	if code, ok := ResponseCodes[responsecode]; ok && code.Category == CategoryApproved {
+		code.Code = responsecode
+		return code
+	}
+	if code, ok := HostResponseCodes[hostresponsecode]; ok && code.Category != CategoryApproved {
+		code.Code = hostresponsecode
+		return code
+	}
+	if code, ok := ResponseCodes[responsecode]; ok {
+		code.Code = responsecode
+		return code
+	}
+	code := unknownCode
+	code.Code = responsecode
+	return code
+}
+
+func (code Code) Message(lang string) string {
+	if strings.EqualFold(lang, "EN") {
+		return code.EN
+	}
+	return code.TR
+}
+
+func (res Response) Message(req *Request) string {
+	lang := ""
+	if req != nil {
+		lang = value(req.Lang)
+	}
+	return res.Code().Message(lang)
+}
+
+func (res Response) Code() Code {
+	return LookupCode(value(res.ResponseCode), value(res.HostResponseCode))
+}
+
+func (txn TxnDetail) Code() Code {
+	return LookupCode(value(txn.ResponseCode), value(txn.HostResponseCode))
+}
+
+func (e *DeclineError) Code() Code {
+	return LookupCode(e.ResponseCode, e.HostResponseCode)
+}
+
+func (e *DeclineError) Message() string {
+	return e.Code().Message(e.Lang)
+}
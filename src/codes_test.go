@@ -0,0 +1,53 @@
+package akbankpos
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDeclineMessageFollowsLang(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"responseCode":"VPS-1999","hostResponseCode":"51"}`))
+	}))
+	defer server.Close()
+	api := New("merchant", "terminal", "secret", WithBaseURL(server.URL))
+	for lang, want := range map[string]string{"EN": "Insufficient funds", "TR": "Kart limitiniz yetersiz"} {
+		req := api.NewRequest()
+		req.SetLang(lang)
+		req.SetOrderId("order-1")
+		res, err := api.Transaction(context.Background(), req)
+		var decline *DeclineError
+		if !errors.As(err, &decline) {
+			t.Fatalf("err = %v, want decline", err)
+		}
+		if got := decline.Message(); got != want {
+			t.Fatalf("%s: decline message = %q, want %q", lang, got, want)
+		}
+		if got := res.Message(req); got != want {
+			t.Fatalf("%s: response message = %q, want %q", lang, got, want)
+		}
+		if decline.Code().Category != CategorySoftDecline || decline.Temporary() {
+			t.Fatalf("%s: code = %+v", lang, decline.Code())
+		}
+	}
+}
+
+func TestLookupCodeVPSWithoutHost(t *testing.T) {
+	code := LookupCode("VPS-1999", "")
+	if code.Code != "VPS-1999" || code.Category != CategorySystemError || code.Retryable {
+		t.Fatalf("code = %+v", code)
+	}
+	ResponseCodes["VPS-1998"] = Code{Category: CategoryRetryable, Retryable: true, TR: "Tekrar deneyiniz", EN: "Please try again"}
+	defer delete(ResponseCodes, "VPS-1998")
+	code = LookupCode("VPS-1998", "")
+	if code.Code != "VPS-1998" || code.Category != CategoryRetryable || !code.Retryable || code.Message("EN") != "Please try again" {
+		t.Fatalf("code = %+v", code)
+	}
+	decline := &DeclineError{ResponseCode: "VPS-1998", Lang: "TR"}
+	if !decline.Temporary() || decline.Message() != "Tekrar deneyiniz" {
+		t.Fatalf("decline = %+v", decline.Code())
+	}
+}
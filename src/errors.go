@@ -0,0 +1,158 @@
+package akbankpos
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type TransportError struct {
+	Err error
+}
+
+func (e *TransportError) Error() string {
+	return "transport error: " + e.Err.Error()
+}
+
+func (e *TransportError) Unwrap() error {
+	return e.Err
+}
+
+func (e *TransportError) Temporary() bool {
+	// a cancelled or timed out payment may have reached the bank; query the order instead of retrying
+	return !errors.Is(e.Err, context.Canceled) && !errors.Is(e.Err, context.DeadlineExceeded)
+}
+
+type GatewayError struct {
+	StatusCode int
+	Code       string
+	Message    string
+	Errors     []Errors
+	Body       []byte
+}
+
+func (e *GatewayError) Error() string {
+	msg := "gateway error (" + strconv.Itoa(e.StatusCode) + ")"
+	if e.Code != "" {
+		msg += " " + e.Code
+	}
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+func (e *GatewayError) Temporary() bool {
+	return e.StatusCode >= http.StatusInternalServerError || e.StatusCode == http.StatusTooManyRequests
+}
+
+type ValidationError struct {
+	StatusCode int
+	Code       string
+	Message    string
+	Errors     []Errors
+	Body       []byte
+}
+
+func (e *ValidationError) Error() string {
+	items := []string{}
+	for _, err := range e.Errors {
+		if err.Code != "" {
+			items = append(items, err.Code+": "+err.Message)
+		} else {
+			items = append(items, err.Message)
+		}
+	}
+	msg := "validation error"
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	if len(items) > 0 {
+		msg += " [" + strings.Join(items, "; ") + "]"
+	}
+	return msg
+}
+
+func (e *ValidationError) Temporary() bool {
+	return false
+}
+
+type DeclineError struct {
+	StatusCode       int
+	ResponseCode     string
+	ResponseMessage  string
+	HostResponseCode string
+	HostMessage      string
+	Lang             string
+	Body             []byte
+}
+
+func (e *DeclineError) Error() string {
+	msg := "declined: " + e.ResponseCode
+	if e.ResponseMessage != "" {
+		msg += " " + e.ResponseMessage
+	}
+	if e.HostResponseCode != "" {
+		msg += " (host " + e.HostResponseCode
+		if e.HostMessage != "" {
+			msg += " " + e.HostMessage
+		}
+		msg += ")"
+	}
+	return msg
+}
+
+func (e *DeclineError) Temporary() bool {
+	return e.Code().Retryable
+}
+
+type CurrencyMismatchError struct {
+	Left  string
+	Right string
+}
+
+func (e *CurrencyMismatchError) Error() string {
+	return "currency mismatch: " + e.Left + " and " + e.Right
+}
+
+func value(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func responseError(status int, body []byte, res Response, lang string) error {
+	if status == http.StatusOK {
+		if value(res.ResponseCode) == "VPS-0000" {
+			return nil
+		}
+		return &DeclineError{
+			StatusCode:       status,
+			ResponseCode:     value(res.ResponseCode),
+			ResponseMessage:  value(res.ResponseMessage),
+			HostResponseCode: value(res.HostResponseCode),
+			HostMessage:      value(res.HostMessage),
+			Lang:             lang,
+			Body:             body,
+		}
+	}
+	if res.Error != nil && len(res.Error.Errors) > 0 && (status == http.StatusBadRequest || status == http.StatusUnprocessableEntity) {
+		return &ValidationError{
+			StatusCode: status,
+			Code:       res.Error.Code,
+			Message:    res.Error.Message,
+			Errors:     res.Error.Errors,
+			Body:       body,
+		}
+	}
+	err := &GatewayError{StatusCode: status, Body: body}
+	if res.Error != nil {
+		err.Code = res.Error.Code
+		err.Message = res.Error.Message
+		err.Errors = res.Error.Errors
+	}
+	return err
+}
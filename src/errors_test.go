@@ -0,0 +1,34 @@
+package akbankpos
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+type doerFunc func(*http.Request) (*http.Response, error)
+
+func (f doerFunc) Do(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestTransportErrorTemporary(t *testing.T) {
+	api := New("merchant", "terminal", "secret", WithDoer(doerFunc(func(r *http.Request) (*http.Response, error) {
+		<-r.Context().Done()
+		return nil, r.Context().Err()
+	})))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := api.QueryOrder(ctx, "order-1")
+	var terr *TransportError
+	if !errors.As(err, &terr) || !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want cancelled transport error", err)
+	}
+	if terr.Temporary() {
+		t.Fatal("cancelled request reported as temporary")
+	}
+	if !(&TransportError{Err: errors.New("connection reset")}).Temporary() {
+		t.Fatal("connection error reported as permanent")
+	}
+}
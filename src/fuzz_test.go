@@ -0,0 +1,266 @@
+package akbankpos
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"testing"
+	"time"
+)
+
+const approved = `{"responseCode":"VPS-0000","hostResponseCode":"00","txnDetailList":[{"txnCode":"1004","responseCode":"VPS-0000","orderId":"order-1","amount":100.00,"currencyCode":949,"preAuthStatus":"O"}],"installmentConditionList":[{"installmentCount":1,"cardType":"COMMERCIAL"}],"reward":{"ccbBalanceRewardAmount":10.00}}`
+
+type shape struct {
+	data []byte
+}
+
+func (s *shape) bit() bool {
+	if len(s.data) == 0 {
+		return false
+	}
+	b := s.data[0]
+	s.data = s.data[1:]
+	return b&1 == 1
+}
+
+func (s *shape) pick(options ...string) string {
+	if len(s.data) == 0 {
+		return ""
+	}
+	b := s.data[0]
+	s.data = s.data[1:]
+	return options[int(b)%len(options)]
+}
+
+func (s *shape) str(options ...string) *string {
+	if !s.bit() {
+		return nil
+	}
+	v := s.pick(options...)
+	return &v
+}
+
+func (s *shape) request(api *API) *Request {
+	if !s.bit() {
+		return nil
+	}
+	req := new(Request)
+	if s.bit() {
+		req = api.NewRequest()
+	}
+	req.Lang = s.str("TR", "EN")
+	req.OkUrl = s.str("", "https://example.com/ok")
+	req.FailUrl = s.str("", "https://example.com/fail")
+	req.ReferenceId = s.str("", "ref-1")
+	if s.bit() {
+		req.Terminal = &Terminal{MerchantSafeId: s.str("", "merchant"), TerminalSafeId: s.str("", "terminal")}
+	}
+	if s.bit() {
+		req.Card = &Card{CardNumber: s.str("", "4355084355084358", "4355", "4355084355084350"), CardExpiry: s.str("", "1299", "0100", "13"), CardCode: s.str("", "000", "1")}
+	}
+	if s.bit() {
+		req.InsurancePan = &InsurancePan{BinNumber: s.str("", "435508"), CardLastFourParam: s.str("", "4358"), IdentityNumber: s.str("", "10000000146")}
+	}
+	if s.bit() {
+		req.Order = &Order{OrderId: s.str("", "order-1"), OrderTrackId: s.str("", "track-1")}
+	}
+	if s.bit() {
+		req.Transaction = new(Transaction)
+		if s.bit() {
+			amount, _ := ParseMoney(s.pick("0", "1", "50.00", "100.00", "-5"), "TRY")
+			req.Transaction.Amount = &amount
+		}
+		if s.bit() {
+			currency := Currency(949)
+			req.Transaction.Currency = &currency
+		}
+		if s.bit() {
+			installment := int(len(s.data) % 15)
+			req.Transaction.Installment = &installment
+		}
+	}
+	if s.bit() {
+		req.Customer = &Customer{EmailAddress: s.str("", "a@example.com"), IpAddress: s.str("", "127.0.0.1")}
+	}
+	if s.bit() {
+		req.Reward = new(Reward)
+		if s.bit() {
+			amount := NewMoney(500, "TRY")
+			req.Reward.CcbRewardAmount = &amount
+		}
+	}
+	if s.bit() {
+		req.Recurring = new(Recurring)
+		if s.bit() {
+			n := 3
+			req.Recurring.NumberOfPayments = &n
+			req.Recurring.FrequencyInterval = &n
+		}
+		req.Recurring.FrequencyCycle = s.str("", "M", "X")
+	}
+	if s.bit() {
+		req.PlannedDate = &PlannedDate{FirstPlannedDate: s.str("", "20990101")}
+	}
+	if s.bit() {
+		req.PayByLink = &PayByLink{LinkTransferType: s.str("", "SMS", "EMAIL"), MobilePhoneNumber: s.str("", "5550000000"), Email: s.str("", "a@example.com")}
+	}
+	if s.bit() {
+		req.SecureTransaction = &SecureTransaction{SecureId: s.str("", "id"), SecureData: s.str("", "data")}
+	}
+	if s.bit() {
+		req.SubMerchant = &SubMerchant{SubMerchantId: s.str("", "sub-1")}
+	}
+	if s.bit() {
+		req.B2B = &B2B{IdentityNumber: s.str("", "10000000146", "0150015264")}
+	}
+	if s.bit() {
+		req.SGK = new(SGK)
+		if s.bit() {
+			amount := NewMoney(s.minor(), "TRY")
+			req.SGK.SurchargeAmount = &amount
+		}
+	}
+	if s.bit() {
+		req.Report = &Report{StartDateTime: s.str("", "2024-01-01T00:00:00.000")}
+	}
+	return req
+}
+
+func (s *shape) minor() int64 {
+	if len(s.data) == 0 {
+		return 0
+	}
+	b := s.data[0]
+	s.data = s.data[1:]
+	return int64(b)
+}
+
+func (s *shape) callback() *Callback {
+	if !s.bit() {
+		return nil
+	}
+	return &Callback{
+		TxnCode:      s.str("", "3000", "3004", "1000"),
+		MdStatus:     s.str("", "0", "1"),
+		PaymentModel: s.str("", "3D", "3D_PAY"),
+		OrderId:      s.str("", "order-1"),
+		Amount:       s.str("", "x", "100.00"),
+		Currency:     s.str("", "949", "XXX"),
+		Installment:  s.str("", "x", "1"),
+	}
+}
+
+func sorted[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func FuzzOperations(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte{1})
+	f.Add(bytes.Repeat([]byte{1}, 256))
+	f.Add([]byte{1, 0, 1, 0, 1, 0, 1, 0, 1, 0, 1, 0, 1, 0, 1, 0, 1, 0, 1, 0, 1, 0, 1, 0, 1, 0, 1, 0, 1, 0, 1, 0})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(approved))
+	}))
+	defer server.Close()
+	f.Fuzz(func(t *testing.T, data []byte) {
+		s := &shape{data: data}
+		api := New("merchant", "terminal", "secret", WithBaseURL(server.URL), WithTimeout(time.Second))
+		api.VerifyResponse = s.bit()
+		if s.bit() {
+			api.SubMerchants = NewSubMerchantRegistry()
+		}
+		ctx := context.Background()
+		if s.bit() {
+			ctx = nil
+		}
+		req := s.request(api)
+		cb := s.callback()
+		operations := map[string]func(context.Context, *Request) (Response, error){
+			"Auth":                api.Auth,
+			"PreAuth":             api.PreAuth,
+			"AuthMoto":            api.AuthMoto,
+			"PreAuthMoto":         api.PreAuthMoto,
+			"Auth3D":              api.Auth3D,
+			"PreAuth3D":           api.PreAuth3D,
+			"PostAuth":            api.PostAuth,
+			"Refund":              api.Refund,
+			"Cancel":              api.Cancel,
+			"Transaction":         api.Transaction,
+			"AuthB2B":             api.AuthB2B,
+			"PreAuthB2B":          api.PreAuthB2B,
+			"SGKPayment":          api.SGKPayment,
+			"InsurancePanSale":    api.InsurancePanSale,
+			"CreatePayLink":       api.CreatePayLink,
+			"GetPayLink":          api.GetPayLink,
+			"CancelPayLink":       api.CancelPayLink,
+			"ListPayLinks":        api.ListPayLinks,
+			"CreateRecurring":     api.CreateRecurring,
+			"QueryRecurring":      api.QueryRecurring,
+			"CancelRecurring":     api.CancelRecurring,
+			"UpdateRecurringCard": api.UpdateRecurringCard,
+			"AuthWithRewards": func(ctx context.Context, req *Request) (Response, error) {
+				return api.AuthWithRewards(ctx, req, nil)
+			},
+			"RefundPartial": func(ctx context.Context, req *Request) (Response, error) {
+				res, _, err := api.RefundPartial(ctx, req)
+				return res, err
+			},
+			"CapturePartial": func(ctx context.Context, req *Request) (Response, error) {
+				res, _, err := api.CapturePartial(ctx, req)
+				return res, err
+			},
+			"Complete3D": func(ctx context.Context, req *Request) (Response, error) {
+				return api.Complete3D(ctx, cb, req)
+			},
+		}
+		html := map[string]func(context.Context, *Request) (string, error){
+			"Auth3Dhtml":    api.Auth3Dhtml,
+			"PreAuth3Dhtml": api.PreAuth3Dhtml,
+			"Transaction3D": api.Transaction3D,
+		}
+		for _, name := range sorted(operations) {
+			op := operations[name]
+			func() {
+				defer func() {
+					if r := recover(); r != nil {
+						t.Fatalf("%s panicked: %v", name, r)
+					}
+				}()
+				op(ctx, req.Clone())
+			}()
+		}
+		for _, name := range sorted(html) {
+			op := html[name]
+			func() {
+				defer func() {
+					if r := recover(); r != nil {
+						t.Fatalf("%s panicked: %v", name, r)
+					}
+				}()
+				op(ctx, req.Clone())
+			}()
+		}
+		defer func() {
+			if r := recover(); r != nil {
+				t.Fatalf("panicked: %v", r)
+			}
+		}()
+		api.QueryOrder(ctx, value(s.str("", "order-1")))
+		api.ListAllTransactions(ctx, time.Now().Add(-time.Hour), time.Now(), TxnFilter{})
+		api.InstallmentOptions(ctx, s.pick("", "435508", "43550843"), s.pick("", "x", "100.00"), s.pick("", "TRY", "XXX"))
+		api.PreAuthPartialCancel(ctx, value(s.str("", "order-1")), s.pick("", "x", "10.00"))
+		if req != nil {
+			api.QueryRewards(ctx, req.Card)
+		}
+		api.Verify3D(url.Values{"hash": {s.pick("", "x")}, "hashParams": {s.pick("", "orderId")}})
+	})
+}
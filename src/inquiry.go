@@ -0,0 +1,142 @@
+package akbankpos
+
+import (
+	"context"
+	"time"
+)
+
+type TxnStatus string
+
+const (
+	TxnStatusSuccess   TxnStatus = "S"
+	TxnStatusFailed    TxnStatus = "F"
+	TxnStatusCancelled TxnStatus = "V"
+	TxnStatusRefunded  TxnStatus = "R"
+)
+
+type PreAuthStatus string
+
+const (
+	PreAuthStatusOpen      PreAuthStatus = "O"
+	PreAuthStatusClosed    PreAuthStatus = "C"
+	PreAuthStatusCancelled PreAuthStatus = "V"
+)
+
+type TxnFilter struct {
+	TxnCode   string
+	TxnStatus TxnStatus
+	OrderId   string
+}
+
+type TxnList struct {
+	Items    []*TxnDetail
+	Response Response
+}
+
+type TxnPage struct {
+	Items    []*TxnDetail
+	Page     int
+	PageSize int
+	Total    int
+	HasMore  bool
+}
+
+func (txn TxnDetail) Status() TxnStatus {
+	return TxnStatus(value(txn.TxnStatus))
+}
+
+func (txn TxnDetail) PreAuth() PreAuthStatus {
+	return PreAuthStatus(value(txn.PreAuthStatus))
+}
+
+func (txn TxnDetail) Approved() bool {
+	if value(txn.ResponseCode) != "VPS-0000" {
+		return false
+	}
+	switch txn.Status() {
+	case TxnStatusFailed, TxnStatusCancelled:
+		return false
+	}
+	return true
+}
+
+func (filter TxnFilter) match(txn *TxnDetail) bool {
+	if txn == nil {
+		return false
+	}
+	if filter.TxnCode != "" && filter.TxnCode != value(txn.TxnCode) {
+		return false
+	}
+	if filter.TxnStatus != "" && filter.TxnStatus != txn.Status() {
+		return false
+	}
+	if filter.OrderId != "" && filter.OrderId != value(txn.OrderId) && filter.OrderId != value(txn.OrgOrderId) {
+		return false
+	}
+	return true
+}
+
+func (api *API) QueryOrder(ctx context.Context, orderid string) (res Response, err error) {
+	if orderid == "" {
+		return res, &ValidationError{Errors: []Errors{{Code: "orderId", Message: "order id is required"}}}
+	}
+	req := api.NewRequest()
+	req.SetOrderId(orderid)
+	if req, err = api.prepare(req, "1010"); err != nil {
+		return res, err
+	}
+	return api.Transaction(ctx, req)
+}
+
+// ListAllTransactions loads every transaction the bank returns for the date range in one request
+// and filters them locally; use TxnList.Page to page through the result.
+func (api *API) ListAllTransactions(ctx context.Context, from, to time.Time, filter TxnFilter) (list TxnList, err error) {
+	if to.Before(from) {
+		return list, &ValidationError{Errors: []Errors{{Code: "report", Message: "end date is before start date"}}}
+	}
+	req := api.NewRequest()
+	start := from.In(Istanbul).Format(DateTimeLayout)
+	end := to.In(Istanbul).Format(DateTimeLayout)
+	req.Report = new(Report)
+	req.Report.StartDateTime = &start
+	req.Report.EndDateTime = &end
+	if req, err = api.prepare(req, "1009"); err != nil {
+		return list, err
+	}
+	res, err := api.Transaction(ctx, req)
+	list.Response = res
+	if err != nil {
+		return list, err
+	}
+	list.Items = []*TxnDetail{}
+	for _, txn := range res.TxnDetailList {
+		if filter.match(txn) {
+			list.Items = append(list.Items, txn)
+		}
+	}
+	return list, nil
+}
+
+// Page slices the already fetched list locally; no paging parameters are sent to the bank.
+func (list TxnList) Page(page, size int) (p TxnPage) {
+	p.Total = len(list.Items)
+	p.Page = page
+	if p.Page < 1 {
+		p.Page = 1
+	}
+	p.PageSize = size
+	if p.PageSize < 1 {
+		p.PageSize = p.Total
+	}
+	first := (p.Page - 1) * p.PageSize
+	if first > p.Total {
+		first = p.Total
+	}
+	last := first + p.PageSize
+	if last > p.Total {
+		last = p.Total
+	}
+	p.Items = list.Items[first:last]
+	p.HasMore = last < p.Total
+	return p
+}
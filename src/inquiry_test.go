@@ -0,0 +1,81 @@
+package akbankpos
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestListAllTransactionsPage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"responseCode":"VPS-0000","txnDetailList":[{"txnCode":"1000","orderId":"a"},{"txnCode":"1002","orderId":"a"},{"txnCode":"1000","orderId":"b"},{"txnCode":"1000","orderId":"c"}]}`))
+	}))
+	defer server.Close()
+	api := New("merchant", "terminal", "secret", WithBaseURL(server.URL))
+	list, err := api.ListAllTransactions(context.Background(), time.Now().Add(-time.Hour), time.Now(), TxnFilter{TxnCode: "1000"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Items) != 3 {
+		t.Fatalf("items = %d, want 3", len(list.Items))
+	}
+	page := list.Page(2, 2)
+	if page.Total != 3 || len(page.Items) != 1 || value(page.Items[0].OrderId) != "c" || page.HasMore {
+		t.Fatalf("page = %+v", page)
+	}
+	if page := list.Page(1, 2); !page.HasMore || len(page.Items) != 2 {
+		t.Fatalf("page = %+v", page)
+	}
+}
+
+func TestQueryOrder(t *testing.T) {
+	api, bank := newBank(t, respond(`{"responseCode":"VPS-0000","txnDetailList":[{"txnCode":"1004","responseCode":"VPS-0000","orderId":"order-1","txnStatus":"S","preAuthStatus":"O"}]}`))
+	var verr *ValidationError
+	if _, err := api.QueryOrder(context.Background(), ""); !errors.As(err, &verr) {
+		t.Fatalf("err = %v, want validation error", err)
+	}
+	res, err := api.QueryOrder(context.Background(), "order-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sent := bank.requests()
+	if len(sent) != 1 || sent[0]["txnCode"] != "1010" {
+		t.Fatalf("sent %v", sent)
+	}
+	if order := sent[0]["order"].(map[string]interface{}); order["orderId"] != "order-1" {
+		t.Fatalf("order = %v", order)
+	}
+	if len(res.TxnDetailList) != 1 || res.TxnDetailList[0].Status() != TxnStatusSuccess || res.TxnDetailList[0].PreAuth() != PreAuthStatusOpen {
+		t.Fatalf("txn details = %+v", res.TxnDetailList)
+	}
+}
+
+func TestTxnDetailStatus(t *testing.T) {
+	for _, test := range []struct {
+		body     string
+		status   TxnStatus
+		preauth  PreAuthStatus
+		approved bool
+	}{
+		{`{"responseCode":"VPS-0000","txnStatus":"S"}`, TxnStatusSuccess, "", true},
+		{`{"responseCode":"VPS-0000","txnStatus":"F"}`, TxnStatusFailed, "", false},
+		{`{"responseCode":"VPS-0000","txnStatus":"V"}`, TxnStatusCancelled, "", false},
+		{`{"responseCode":"VPS-0000","txnStatus":"R"}`, TxnStatusRefunded, "", true},
+		{`{"responseCode":"VPS-1999","txnStatus":"S"}`, TxnStatusSuccess, "", false},
+		{`{"responseCode":"VPS-0000","preAuthStatus":"O"}`, "", PreAuthStatusOpen, true},
+		{`{"responseCode":"VPS-0000","preAuthStatus":"C"}`, "", PreAuthStatusClosed, true},
+		{`{"responseCode":"VPS-0000","preAuthStatus":"V"}`, "", PreAuthStatusCancelled, true},
+	} {
+		var txn TxnDetail
+		if err := json.Unmarshal([]byte(test.body), &txn); err != nil {
+			t.Fatal(err)
+		}
+		if txn.Status() != test.status || txn.PreAuth() != test.preauth || txn.Approved() != test.approved {
+			t.Errorf("%s: status %q, preauth %q, approved %v", test.body, txn.Status(), txn.PreAuth(), txn.Approved())
+		}
+	}
+}
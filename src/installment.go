@@ -0,0 +1,74 @@
+package akbankpos
+
+import (
+	"context"
+)
+
+type InstallmentOption struct {
+	Count    int
+	Type     string
+	CardType string
+}
+
+type InstallmentPlan struct {
+	Options  []InstallmentOption
+	Interest *Interest
+	Response Response
+}
+
+func (plan InstallmentPlan) Allowed(count int) bool {
+	for _, option := range plan.Options {
+		if option.Count == count {
+			return true
+		}
+	}
+	return false
+}
+
+func digits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func (api *API) InstallmentOptions(ctx context.Context, bin, amount, currency string) (plan InstallmentPlan, err error) {
+	req := api.NewRequest()
+	if err := req.SetAmount(amount, currency); err != nil {
+		return plan, &ValidationError{Errors: []Errors{{Code: "amount", Message: err.Error()}}}
+	}
+	return api.installmentPlan(ctx, bin, req)
+}
+
+func (api *API) installmentPlan(ctx context.Context, bin string, req *Request) (plan InstallmentPlan, err error) {
+	if !digits(bin) || (len(bin) != 6 && len(bin) != 8) {
+		return plan, &ValidationError{Errors: []Errors{{Code: "binNumber", Message: "bin must be 6 or 8 digits"}}}
+	}
+	req.Card = new(Card)
+	req.Card.BinNumber = &bin
+	if req, err = api.prepare(req, "1014"); err != nil {
+		return plan, err
+	}
+	res, err := api.Transaction(ctx, req)
+	plan.Response = res
+	if err != nil {
+		return plan, err
+	}
+	plan.Interest = res.Interest
+	for _, item := range res.InstallmentList {
+		if item == nil || item.InstallmentCount == nil {
+			continue
+		}
+		plan.Options = append(plan.Options, InstallmentOption{
+			Count:    int(*item.InstallmentCount),
+			Type:     value(item.InstallmentType),
+			CardType: value(item.CardType),
+		})
+	}
+	return plan, nil
+}
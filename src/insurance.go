@@ -0,0 +1,79 @@
+package akbankpos
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// ids shorter than this reveal too much of themselves through the first and last two characters
+const maskMinLength = 8
+
+func MaskIdentityNumber(id string) string {
+	if len(id) < maskMinLength {
+		return strings.Repeat("*", len(id))
+	}
+	return id[:2] + strings.Repeat("*", len(id)-4) + id[len(id)-2:]
+}
+
+func (pan InsurancePan) String() string {
+	return fmt.Sprintf("{BinNumber:%s CardLastFourParam:%s IdentityNumber:%s}", value(pan.BinNumber), value(pan.CardLastFourParam), MaskIdentityNumber(value(pan.IdentityNumber)))
+}
+
+func (pan InsurancePan) GoString() string {
+	return pan.String()
+}
+
+func (pan InsurancePan) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("binNumber", value(pan.BinNumber)),
+		slog.String("cardLastFourParam", value(pan.CardLastFourParam)),
+		slog.String("identityNumber", MaskIdentityNumber(value(pan.IdentityNumber))),
+	)
+}
+
+func (req *Request) SetInsurancePan(binnumber, cardlastfour, identitynumber string) {
+	if req.InsurancePan == nil {
+		req.InsurancePan = new(InsurancePan)
+	}
+	req.InsurancePan.BinNumber = &binnumber
+	req.InsurancePan.CardLastFourParam = &cardlastfour
+	req.InsurancePan.IdentityNumber = &identitynumber
+}
+
+func validateInsurancePan(req *Request) error {
+	if req == nil {
+		return nilRequest()
+	}
+	if req.InsurancePan == nil {
+		return &ValidationError{Errors: []Errors{{Code: "insurancePan", Message: "insurance pan is required"}}}
+	}
+	errs := []Errors{}
+	if bin := value(req.InsurancePan.BinNumber); !digits(bin) || (len(bin) != 6 && len(bin) != 8) {
+		errs = append(errs, Errors{Code: "binNumber", Message: "bin must be 6 or 8 digits"})
+	}
+	if last := value(req.InsurancePan.CardLastFourParam); !digits(last) || len(last) != 4 {
+		errs = append(errs, Errors{Code: "cardLastFourParam", Message: "card last four digits must be 4 digits"})
+	}
+	if id := value(req.InsurancePan.IdentityNumber); !ValidIdentityNumber(id) {
+		errs = append(errs, Errors{Code: "identityNumber", Message: "identity number " + MaskIdentityNumber(id) + " is not a valid TCKN or VKN"})
+	}
+	if req.Card != nil && value(req.Card.CardNumber) != "" {
+		errs = append(errs, Errors{Code: "cardNumber", Message: "card number must not be sent with insurance pan"})
+	}
+	if req.Transaction == nil || money(req.Transaction.Amount).Minor <= 0 {
+		errs = append(errs, Errors{Code: "amount", Message: "amount must be positive"})
+	}
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+	return nil
+}
+
+func (api *API) InsurancePanSale(ctx context.Context, req *Request) (res Response, err error) {
+	if err := validateInsurancePan(req); err != nil {
+		return res, err
+	}
+	return api.Auth(ctx, req)
+}
@@ -0,0 +1,70 @@
+package akbankpos
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestMaskIdentityNumber(t *testing.T) {
+	for id, want := range map[string]string{
+		"":            "",
+		"1234":        "****",
+		"12345":       "*****",
+		"1234567":     "*******",
+		"12345678":    "12****78",
+		"0150015264":  "01******64",
+		"10000000146": "10*******46",
+	} {
+		if got := MaskIdentityNumber(id); got != want {
+			t.Errorf("MaskIdentityNumber(%q) = %q, want %q", id, got, want)
+		}
+	}
+}
+
+func TestInsurancePanMasked(t *testing.T) {
+	req := New("merchant", "terminal", "secret").NewRequest()
+	req.SetInsurancePan("435508", "4358", "10000000146")
+	var buf bytes.Buffer
+	slog.New(slog.NewTextHandler(&buf, nil)).Info("sale", "pan", *req.InsurancePan)
+	for name, out := range map[string]string{
+		"String":   req.InsurancePan.String(),
+		"GoString": fmt.Sprintf("%#v", *req.InsurancePan),
+		"LogValue": buf.String(),
+	} {
+		if strings.Contains(out, "10000000146") || !strings.Contains(out, "10*******46") {
+			t.Errorf("%s: %s", name, out)
+		}
+	}
+}
+
+func TestInsurancePanSale(t *testing.T) {
+	api, bank := newBank(t, respond(`{"responseCode":"VPS-0000"}`))
+	req := api.NewRequest()
+	req.SetOrderId("order-1")
+	req.SetAmount("10", "TRY")
+	req.SetInsurancePan("435508", "4358", "10000000147")
+	var verr *ValidationError
+	_, err := api.InsurancePanSale(context.Background(), req)
+	if !errors.As(err, &verr) {
+		t.Fatalf("err = %v, want validation error", err)
+	}
+	if msg := err.Error(); strings.Contains(msg, "10000000147") || !strings.Contains(msg, "identity number 10*******47 is not a valid TCKN or VKN") {
+		t.Fatalf("error = %q", msg)
+	}
+	req.SetInsurancePan("435508", "4358", "10000000146")
+	if _, err := api.InsurancePanSale(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+	sent := bank.requests()
+	if len(sent) != 1 || sent[0]["txnCode"] != "1000" || sent[0]["card"] != nil {
+		t.Fatalf("sent %v", sent)
+	}
+	if pan := sent[0]["insurancePan"].(map[string]interface{}); pan["identityNumber"] != "10000000146" {
+		t.Fatalf("insurance pan = %v", pan)
+	}
+}
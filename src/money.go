@@ -0,0 +1,159 @@
+package akbankpos
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+type Money struct {
+	Minor    int64
+	Currency string
+}
+
+func NewMoney(minor int64, currency string) Money {
+	return Money{Minor: minor, Currency: currency}
+}
+
+func ParseMoney(amount, currency string) (Money, error) {
+	if currency != "" {
+		code, err := LookupCurrency(currency)
+		if err != nil {
+			return Money{}, err
+		}
+		currency = code.Alpha()
+	}
+	minor, err := parseMinor(amount, exponent(currency), false)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{Minor: minor, Currency: currency}, nil
+}
+
+func parseMinor(amount string, exp int, round bool) (int64, error) {
+	invalid := errors.New("invalid amount: " + amount)
+	s := amount
+	negative := strings.HasPrefix(s, "-")
+	if negative {
+		s = s[1:]
+	}
+	whole, frac, _ := strings.Cut(s, ".")
+	if !digits(whole) || (frac != "" && !digits(frac)) {
+		return 0, invalid
+	}
+	trimmed := strings.TrimRight(frac, "0")
+	up := false
+	if len(trimmed) > exp {
+		if !round {
+			return 0, errors.New("amount has more decimals than the currency allows: " + amount)
+		}
+		// half away from zero, applied to the magnitude before the sign
+		up = trimmed[exp] >= '5'
+		trimmed = trimmed[:exp]
+	}
+	frac = trimmed + strings.Repeat("0", exp-len(trimmed))
+	minor, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil {
+		return 0, invalid
+	}
+	if up {
+		minor++
+	}
+	if negative {
+		minor = -minor
+	}
+	return minor, nil
+}
+
+func (m Money) String() string {
+	exp := exponent(m.Currency)
+	n := m.Minor
+	sign := ""
+	if n < 0 {
+		sign = "-"
+		n = -n
+	}
+	s := strconv.FormatInt(n, 10)
+	if exp == 0 {
+		return sign + s
+	}
+	if len(s) <= exp {
+		s = strings.Repeat("0", exp-len(s)+1) + s
+	}
+	return sign + s[:len(s)-exp] + "." + s[len(s)-exp:]
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+func (m *Money) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "null" || s == "" {
+		return nil
+	}
+	// the bank's amounts are decoded leniently; a response must never fail on precision
+	minor, err := parseMinor(s, exponent(m.Currency), true)
+	if err != nil {
+		f, ferr := strconv.ParseFloat(s, 64)
+		if ferr != nil {
+			return err
+		}
+		if minor, err = parseMinor(strconv.FormatFloat(f, 'f', -1, 64), exponent(m.Currency), true); err != nil {
+			return err
+		}
+	}
+	m.Minor = minor
+	return nil
+}
+
+func (m Money) IsZero() bool {
+	return m.Minor == 0
+}
+
+func (m Money) compatible(o Money) (string, error) {
+	// an empty currency is unspecified and takes the other operand's currency
+	switch {
+	case m.Currency == "":
+		return o.Currency, nil
+	case o.Currency == "" || o.Currency == m.Currency:
+		return m.Currency, nil
+	}
+	return "", &CurrencyMismatchError{Left: m.Currency, Right: o.Currency}
+}
+
+func (m Money) Add(o Money) (Money, error) {
+	currency, err := m.compatible(o)
+	if err != nil {
+		return m, err
+	}
+	return Money{Minor: m.Minor + o.Minor, Currency: currency}, nil
+}
+
+func (m Money) Sub(o Money) (Money, error) {
+	currency, err := m.compatible(o)
+	if err != nil {
+		return m, err
+	}
+	return Money{Minor: m.Minor - o.Minor, Currency: currency}, nil
+}
+
+func (m Money) Cmp(o Money) (int, error) {
+	if _, err := m.compatible(o); err != nil {
+		return 0, err
+	}
+	switch {
+	case m.Minor < o.Minor:
+		return -1, nil
+	case m.Minor > o.Minor:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+func money(m *Money) Money {
+	if m == nil {
+		return Money{}
+	}
+	return *m
+}
@@ -0,0 +1,63 @@
+package akbankpos
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestMoneyWireFormat(t *testing.T) {
+	for _, test := range []struct {
+		amount, currency, want string
+	}{
+		{"123456.78", "TRY", `{"amount":123456.78,"currencyCode":949}`},
+		{"1500", "JPY", `{"amount":1500,"currencyCode":392}`},
+		{"0.1", "USD", `{"amount":0.10,"currencyCode":840}`},
+	} {
+		req := new(Request)
+		if err := req.SetAmount(test.amount, test.currency); err != nil {
+			t.Fatal(err)
+		}
+		got, err := json.Marshal(req.Transaction)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, []byte(test.want)) {
+			t.Fatalf("%s %s = %s, want %s", test.amount, test.currency, got, test.want)
+		}
+	}
+	if err := new(Request).SetAmount("1500.5", "JPY"); err == nil {
+		t.Fatal("accepted decimals for a currency without minor units")
+	}
+	if err := new(Request).SetAmount("1.005", "TRY"); err == nil {
+		t.Fatal("accepted three decimals for TRY")
+	}
+}
+
+func TestMoneyResponseRounding(t *testing.T) {
+	for _, test := range []struct {
+		body, want string
+	}{
+		{`{"amount":10.125,"currencyCode":949}`, "10.13"},
+		{`{"amount":10.124,"currencyCode":949}`, "10.12"},
+		{`{"amount":1500.5,"currencyCode":392}`, "1501"},
+		{`{"amount":1.5E+2,"currencyCode":949}`, "150.00"},
+	} {
+		var txn Transaction
+		if err := json.Unmarshal([]byte(test.body), &txn); err != nil {
+			t.Fatalf("%s: %v", test.body, err)
+		}
+		if got := txn.Amount.String(); got != test.want {
+			t.Fatalf("%s = %s, want %s", test.body, got, test.want)
+		}
+	}
+	api, _ := newBank(t, respond(`{"responseCode":"VPS-0000","transaction":{"amount":10.125,"currencyCode":949}}`))
+	res, err := api.QueryOrder(context.Background(), "order-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Transaction.Amount.String() != "10.13" {
+		t.Fatalf("amount = %s", res.Transaction.Amount)
+	}
+}
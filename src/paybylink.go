@@ -0,0 +1,122 @@
+package akbankpos
+
+import "context"
+
+type LinkTransferType string
+
+const (
+	LinkTransferSMS   LinkTransferType = "SMS"
+	LinkTransferEmail LinkTransferType = "EMAIL"
+)
+
+type LinkStatus string
+
+const (
+	LinkStatusActive    LinkStatus = "ACTIVE"
+	LinkStatusPaid      LinkStatus = "PAID"
+	LinkStatusExpired   LinkStatus = "EXPIRED"
+	LinkStatusCancelled LinkStatus = "CANCELLED"
+	LinkStatusUnknown   LinkStatus = ""
+)
+
+func (detail LinkDetail) Status() LinkStatus {
+	switch status := LinkStatus(value(detail.LinkStatus)); status {
+	case LinkStatusActive, LinkStatusPaid, LinkStatusExpired, LinkStatusCancelled:
+		return status
+	}
+	return LinkStatusUnknown
+}
+
+func (req *Request) SetReferenceId(referenceid string) {
+	req.ReferenceId = &referenceid
+}
+
+func (req *Request) SetLinkTransfer(transfertype LinkTransferType, contact string) {
+	if req.PayByLink == nil {
+		req.PayByLink = new(PayByLink)
+	}
+	linktransfertype := string(transfertype)
+	req.PayByLink.LinkTransferType = &linktransfertype
+	switch transfertype {
+	case LinkTransferSMS:
+		req.PayByLink.MobilePhoneNumber = &contact
+	case LinkTransferEmail:
+		req.PayByLink.Email = &contact
+	}
+}
+
+func (req *Request) SetLinkPreAuth() {
+	if req.PayByLink == nil {
+		req.PayByLink = new(PayByLink)
+	}
+	code := "1004"
+	req.PayByLink.LinkTxnCode = &code
+}
+
+func (api *API) CreatePayLink(ctx context.Context, req *Request) (res Response, err error) {
+	if req == nil {
+		return res, nilRequest()
+	}
+	if req.PayByLink == nil {
+		return res, &ValidationError{Errors: []Errors{{Code: "payByLink", Message: "link transfer type is required"}}}
+	}
+	switch LinkTransferType(value(req.PayByLink.LinkTransferType)) {
+	case LinkTransferSMS:
+		if value(req.PayByLink.MobilePhoneNumber) == "" {
+			return res, &ValidationError{Errors: []Errors{{Code: "mobilePhoneNumber", Message: "mobile phone number is required for sms links"}}}
+		}
+	case LinkTransferEmail:
+		if value(req.PayByLink.Email) == "" {
+			return res, &ValidationError{Errors: []Errors{{Code: "email", Message: "email is required for email links"}}}
+		}
+	default:
+		return res, &ValidationError{Errors: []Errors{{Code: "linkTransferType", Message: "unsupported link transfer type"}}}
+	}
+	if errs := req.validateAmount(nil); len(errs) > 0 {
+		return res, &ValidationError{Errors: errs}
+	}
+	if req, err = api.prepare(req, "1100"); err != nil {
+		return res, err
+	}
+	if req.PayByLink.LinkTxnCode == nil {
+		linkcode := "1000"
+		req.PayByLink.LinkTxnCode = &linkcode
+	}
+	return api.Transaction(ctx, req)
+}
+
+func (api *API) GetPayLink(ctx context.Context, req *Request) (res Response, err error) {
+	if req == nil {
+		return res, nilRequest()
+	}
+	if value(req.ReferenceId) == "" {
+		return res, &ValidationError{Errors: []Errors{{Code: "referenceId", Message: "reference id is required"}}}
+	}
+	if req, err = api.prepare(req, "1101"); err != nil {
+		return res, err
+	}
+	return api.Transaction(ctx, req)
+}
+
+func (api *API) CancelPayLink(ctx context.Context, req *Request) (res Response, err error) {
+	if req == nil {
+		return res, nilRequest()
+	}
+	if value(req.ReferenceId) == "" {
+		return res, &ValidationError{Errors: []Errors{{Code: "referenceId", Message: "reference id is required"}}}
+	}
+	if req, err = api.prepare(req, "1102"); err != nil {
+		return res, err
+	}
+	return api.Transaction(ctx, req)
+}
+
+func (api *API) ListPayLinks(ctx context.Context, req *Request) (res Response, err error) {
+	if req == nil {
+		return res, nilRequest()
+	}
+	if req, err = api.prepare(req, "1103"); err != nil {
+		return res, err
+	}
+	return api.Transaction(ctx, req)
+}
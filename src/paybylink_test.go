@@ -0,0 +1,80 @@
+package akbankpos
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestPayLinkOperations(t *testing.T) {
+	api, bank := newBank(t, respond(`{"responseCode":"VPS-0000"}`))
+	ctx := context.Background()
+	var verr *ValidationError
+	req := api.NewRequest()
+	req.SetLinkTransfer(LinkTransferEmail, "buyer@example.com")
+	if _, err := api.CreatePayLink(ctx, req); !errors.As(err, &verr) {
+		t.Fatalf("create without amount err = %v, want validation error", err)
+	}
+	req.SetAmount("25.50", "TRY")
+	if _, err := api.CreatePayLink(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	req.SetLinkPreAuth()
+	if _, err := api.CreatePayLink(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := api.GetPayLink(ctx, api.NewRequest()); !errors.As(err, &verr) {
+		t.Fatalf("get without reference err = %v, want validation error", err)
+	}
+	if _, err := api.CancelPayLink(ctx, api.NewRequest()); !errors.As(err, &verr) {
+		t.Fatalf("cancel without reference err = %v, want validation error", err)
+	}
+	req = api.NewRequest()
+	req.SetReferenceId("ref-1")
+	if _, err := api.GetPayLink(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := api.CancelPayLink(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := api.ListPayLinks(ctx, api.NewRequest()); err != nil {
+		t.Fatal(err)
+	}
+	requests := bank.requests()
+	codes := bank.codes()
+	if len(codes) != 5 || codes[0] != "1100" || codes[1] != "1100" || codes[2] != "1101" || codes[3] != "1102" || codes[4] != "1103" {
+		t.Fatalf("txn codes = %v, want [1100 1100 1101 1102 1103]", codes)
+	}
+	for i, want := range []string{"1000", "1004"} {
+		link := requests[i]["payByLink"].(map[string]interface{})
+		if link["linkTxnCode"] != want || link["email"] != "buyer@example.com" {
+			t.Fatalf("link %d = %v", i, link)
+		}
+		if transaction := requests[i]["transaction"].(map[string]interface{}); transaction["amount"] != 25.5 {
+			t.Fatalf("transaction %d = %v", i, transaction)
+		}
+	}
+	if requests[2]["referenceId"] != "ref-1" || requests[3]["referenceId"] != "ref-1" {
+		t.Fatalf("reference ids = %v, %v", requests[2]["referenceId"], requests[3]["referenceId"])
+	}
+}
+
+func TestLinkDetailStatus(t *testing.T) {
+	for body, want := range map[string]LinkStatus{
+		`{"linkStatus":"ACTIVE"}`:    LinkStatusActive,
+		`{"linkStatus":"PAID"}`:      LinkStatusPaid,
+		`{"linkStatus":"EXPIRED"}`:   LinkStatusExpired,
+		`{"linkStatus":"CANCELLED"}`: LinkStatusCancelled,
+		`{"linkStatus":"PENDING"}`:   LinkStatusUnknown,
+		`{}`:                         LinkStatusUnknown,
+	} {
+		var detail LinkDetail
+		if err := json.Unmarshal([]byte(body), &detail); err != nil {
+			t.Fatal(err)
+		}
+		if got := detail.Status(); got != want {
+			t.Errorf("%s: status = %q, want %q", body, got, want)
+		}
+	}
+}
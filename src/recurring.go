@@ -0,0 +1,151 @@
+package akbankpos
+
+import (
+	"context"
+	"sort"
+)
+
+type FrequencyCycle string
+
+const (
+	FrequencyDaily   FrequencyCycle = "D"
+	FrequencyWeekly  FrequencyCycle = "W"
+	FrequencyMonthly FrequencyCycle = "M"
+)
+
+type RecurringPayment struct {
+	RecurringOrder  int
+	OrderId         string
+	PlannedDateTime string
+	TxnDateTime     string
+	RequestStatus   string
+	ResponseCode    string
+	Amount          *Money
+}
+
+func (cycle FrequencyCycle) Valid() bool {
+	switch cycle {
+	case FrequencyDaily, FrequencyWeekly, FrequencyMonthly:
+		return true
+	}
+	return false
+}
+
+func (req *Request) SetRecurring(numberofpayments, frequencyinterval int, frequencycycle FrequencyCycle) {
+	if req.Recurring == nil {
+		req.Recurring = new(Recurring)
+	}
+	cycle := string(frequencycycle)
+	req.Recurring.NumberOfPayments = &numberofpayments
+	req.Recurring.FrequencyInterval = &frequencyinterval
+	req.Recurring.FrequencyCycle = &cycle
+}
+
+func (req *Request) SetFirstPlannedDate(date string) {
+	if req.PlannedDate == nil {
+		req.PlannedDate = new(PlannedDate)
+	}
+	req.PlannedDate.FirstPlannedDate = &date
+}
+
+func (req *Request) SetOrderTrackId(ordertrackid string) {
+	if req.Order == nil {
+		req.Order = new(Order)
+	}
+	req.Order.OrderTrackId = &ordertrackid
+}
+
+func (res Response) Schedule() []RecurringPayment {
+	schedule := []RecurringPayment{}
+	for _, txn := range res.TxnDetailList {
+		if txn == nil || txn.RecurringOrder == nil {
+			continue
+		}
+		schedule = append(schedule, RecurringPayment{
+			RecurringOrder:  *txn.RecurringOrder,
+			OrderId:         value(txn.OrderId),
+			PlannedDateTime: value(txn.PlannedDateTime),
+			TxnDateTime:     value(txn.TxnDateTime),
+			RequestStatus:   value(txn.RequestStatus),
+			ResponseCode:    value(txn.ResponseCode),
+			Amount:          txn.Amount,
+		})
+	}
+	sort.Slice(schedule, func(i, j int) bool {
+		return schedule[i].RecurringOrder < schedule[j].RecurringOrder
+	})
+	return schedule
+}
+
+func (api *API) CreateRecurring(ctx context.Context, req *Request) (res Response, err error) {
+	if req == nil {
+		return res, nilRequest()
+	}
+	if req.Recurring == nil {
+		return res, &ValidationError{Errors: []Errors{{Code: "recurring", Message: "recurring schedule is required"}}}
+	}
+	errs := []Errors{}
+	if req.Recurring.NumberOfPayments == nil || *req.Recurring.NumberOfPayments < 1 {
+		errs = append(errs, Errors{Code: "numberOfPayments", Message: "number of payments must be positive"})
+	}
+	if req.Recurring.FrequencyInterval == nil || *req.Recurring.FrequencyInterval < 1 {
+		errs = append(errs, Errors{Code: "frequencyInterval", Message: "frequency interval must be positive"})
+	}
+	if req.Recurring.FrequencyCycle == nil || !FrequencyCycle(*req.Recurring.FrequencyCycle).Valid() {
+		errs = append(errs, Errors{Code: "frequencyCycle", Message: "frequency cycle must be D, W or M"})
+	}
+	if len(errs) > 0 {
+		return res, &ValidationError{Errors: errs}
+	}
+	if err := req.ValidateAt("1000", api.Now()); err != nil {
+		return res, err
+	}
+	if req, err = api.prepare(req, "1000"); err != nil {
+		return res, err
+	}
+	motoInd := 0
+	req.Transaction.MotoInd = &motoInd
+	return api.Transaction(ctx, req)
+}
+
+func (api *API) QueryRecurring(ctx context.Context, req *Request) (res Response, err error) {
+	if req == nil {
+		return res, nilRequest()
+	}
+	if req.Order == nil || value(req.Order.OrderTrackId) == "" {
+		return res, &ValidationError{Errors: []Errors{{Code: "orderTrackId", Message: "order track id is required"}}}
+	}
+	if req, err = api.prepare(req, "1010"); err != nil {
+		return res, err
+	}
+	return api.Transaction(ctx, req)
+}
+
+func (api *API) CancelRecurring(ctx context.Context, req *Request) (res Response, err error) {
+	if req == nil {
+		return res, nilRequest()
+	}
+	if req.Order == nil || value(req.Order.OrderTrackId) == "" {
+		return res, &ValidationError{Errors: []Errors{{Code: "orderTrackId", Message: "order track id is required"}}}
+	}
+	if req, err = api.prepare(req, "1003"); err != nil {
+		return res, err
+	}
+	return api.Transaction(ctx, req)
+}
+
+func (api *API) UpdateRecurringCard(ctx context.Context, req *Request) (res Response, err error) {
+	if req == nil {
+		return res, nilRequest()
+	}
+	if req.Order == nil || value(req.Order.OrderTrackId) == "" {
+		return res, &ValidationError{Errors: []Errors{{Code: "orderTrackId", Message: "order track id is required"}}}
+	}
+	if req.Card == nil || value(req.Card.CardNumber) == "" || value(req.Card.CardExpiry) == "" {
+		return res, &ValidationError{Errors: []Errors{{Code: "card", Message: "card number and expiry are required"}}}
+	}
+	if req, err = api.prepare(req, "1011"); err != nil {
+		return res, err
+	}
+	return api.Transaction(ctx, req)
+}
@@ -0,0 +1,137 @@
+package akbankpos
+
+import (
+	"context"
+	"errors"
+)
+
+type RewardBalance struct {
+	Ccb      *Money
+	Pcb      *Money
+	Xcb      *Money
+	Response Response
+}
+
+func parseReward(amount, currency string) (*Money, error) {
+	if amount == "" {
+		return nil, nil
+	}
+	reward, err := ParseMoney(amount, currency)
+	if err != nil {
+		return nil, err
+	}
+	return &reward, nil
+}
+
+func (req *Request) SetRewardAmounts(ccb, pcb, xcb string) error {
+	if req.Transaction == nil || req.Transaction.Amount == nil {
+		return errors.New("amount must be set before reward amounts")
+	}
+	currency := req.Transaction.Amount.Currency
+	reward := new(Reward)
+	var err error
+	if reward.CcbRewardAmount, err = parseReward(ccb, currency); err != nil {
+		return err
+	}
+	if reward.PcbRewardAmount, err = parseReward(pcb, currency); err != nil {
+		return err
+	}
+	if reward.XcbRewardAmount, err = parseReward(xcb, currency); err != nil {
+		return err
+	}
+	req.Reward = reward
+	return nil
+}
+
+func (api *API) QueryRewards(ctx context.Context, card *Card) (balance RewardBalance, err error) {
+	if card == nil || value(card.CardNumber) == "" {
+		return balance, &ValidationError{Errors: []Errors{{Code: "cardNumber", Message: "card number is required"}}}
+	}
+	req := api.NewRequest()
+	req.Card = card
+	if req, err = api.prepare(req, "1012"); err != nil {
+		return balance, err
+	}
+	res, err := api.Transaction(ctx, req)
+	balance.Response = res
+	if err != nil {
+		return balance, err
+	}
+	if res.Reward != nil {
+		balance.Ccb = res.Reward.CcbBalanceRewardAmount
+		balance.Pcb = res.Reward.PcbBalanceRewardAmount
+		balance.Xcb = res.Reward.XcbBalanceRewardAmount
+	}
+	return balance, nil
+}
+
+func validateRewards(req *Request, balance *RewardBalance) error {
+	if req == nil {
+		return nilRequest()
+	}
+	if req.Reward == nil {
+		return &ValidationError{Errors: []Errors{{Code: "reward", Message: "reward amounts are required"}}}
+	}
+	if req.Transaction == nil || req.Transaction.Amount == nil {
+		return &ValidationError{Errors: []Errors{{Code: "amount", Message: "amount is required"}}}
+	}
+	errs := []Errors{}
+	rewards := []struct {
+		code    string
+		amount  *Money
+		balance *Money
+	}{
+		{"ccbRewardAmount", req.Reward.CcbRewardAmount, nil},
+		{"pcbRewardAmount", req.Reward.PcbRewardAmount, nil},
+		{"xcbRewardAmount", req.Reward.XcbRewardAmount, nil},
+	}
+	if balance != nil {
+		rewards[0].balance = balance.Ccb
+		rewards[1].balance = balance.Pcb
+		rewards[2].balance = balance.Xcb
+	}
+	var total Money
+	for _, reward := range rewards {
+		if reward.amount == nil {
+			continue
+		}
+		if reward.amount.Minor < 0 {
+			errs = append(errs, Errors{Code: reward.code, Message: "reward amount cannot be negative"})
+			continue
+		}
+		if balance != nil {
+			if reward.balance == nil {
+				errs = append(errs, Errors{Code: reward.code, Message: "reward amount exceeds available balance"})
+			} else if cmp, err := reward.amount.Cmp(*reward.balance); err != nil {
+				errs = append(errs, Errors{Code: reward.code, Message: err.Error()})
+			} else if cmp > 0 {
+				errs = append(errs, Errors{Code: reward.code, Message: "reward amount exceeds available balance"})
+			}
+		}
+		sum, err := total.Add(*reward.amount)
+		if err != nil {
+			errs = append(errs, Errors{Code: reward.code, Message: err.Error()})
+			continue
+		}
+		total = sum
+	}
+	if total.Minor <= 0 {
+		errs = append(errs, Errors{Code: "reward", Message: "at least one reward amount is required"})
+	}
+	if cmp, err := total.Cmp(*req.Transaction.Amount); err != nil {
+		errs = append(errs, Errors{Code: "reward", Message: err.Error()})
+	} else if cmp > 0 {
+		errs = append(errs, Errors{Code: "reward", Message: "reward amounts exceed transaction amount"})
+	}
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+	return nil
+}
+
+func (api *API) AuthWithRewards(ctx context.Context, req *Request, balance *RewardBalance) (res Response, err error) {
+	if err := validateRewards(req, balance); err != nil {
+		return res, err
+	}
+	return api.Auth(ctx, req)
+}
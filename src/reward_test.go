@@ -0,0 +1,23 @@
+package akbankpos
+
+import "testing"
+
+func TestSetRewardAmounts(t *testing.T) {
+	req := new(Request)
+	if err := req.SetRewardAmounts("5", "", ""); err == nil {
+		t.Fatal("expected error when amount is not set")
+	}
+	req.SetAmount("100", "TRY")
+	if err := req.SetRewardAmounts("1,5", "", ""); err == nil {
+		t.Fatal("expected error for invalid reward amount")
+	}
+	if req.Reward != nil {
+		t.Fatal("invalid reward amount changed the request")
+	}
+	if err := req.SetRewardAmounts("1.5", "", "2"); err != nil {
+		t.Fatal(err)
+	}
+	if req.Reward.CcbRewardAmount.String() != "1.50" || req.Reward.PcbRewardAmount != nil || req.Reward.XcbRewardAmount.Currency != "TRY" {
+		t.Fatalf("reward = %+v", req.Reward)
+	}
+}
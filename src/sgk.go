@@ -0,0 +1,58 @@
+package akbankpos
+
+import (
+	"context"
+	"errors"
+)
+
+func (req *Request) SetSGKSurcharge(surcharge string) error {
+	if req.Transaction == nil || req.Transaction.Amount == nil {
+		return errors.New("amount must be set before the sgk surcharge")
+	}
+	amount, err := ParseMoney(surcharge, req.Transaction.Amount.Currency)
+	if err != nil {
+		return err
+	}
+	if req.SGK == nil {
+		req.SGK = new(SGK)
+	}
+	req.SGK.SurchargeAmount = &amount
+	return nil
+}
+
+func validateSGK(req *Request) error {
+	if req == nil {
+		return nilRequest()
+	}
+	errs := []Errors{}
+	if req.SGK == nil || money(req.SGK.SurchargeAmount).Minor <= 0 {
+		errs = append(errs, Errors{Code: "surchargeAmount", Message: "surcharge amount must be positive"})
+	}
+	if req.Transaction == nil || money(req.Transaction.Amount).Minor <= 0 {
+		errs = append(errs, Errors{Code: "amount", Message: "amount must be positive"})
+	} else {
+		if req.SGK != nil {
+			if cmp, err := money(req.SGK.SurchargeAmount).Cmp(*req.Transaction.Amount); err != nil {
+				errs = append(errs, Errors{Code: "surchargeAmount", Message: err.Error()})
+			} else if cmp >= 0 {
+				errs = append(errs, Errors{Code: "surchargeAmount", Message: "surcharge amount must be less than the total amount"})
+			}
+		}
+		if req.Transaction.Installment != nil && *req.Transaction.Installment > 1 {
+			errs = append(errs, Errors{Code: "installCount", Message: "sgk payments cannot be made in installments"})
+		}
+	}
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+	return nil
+}
+
+// SGKPayment is a regular sale carrying the SGK surcharge. Refunds go through Refund or RefundPartial;
+// SGK-specific refund rules, such as whether the surcharge is refundable, are out of scope here.
+func (api *API) SGKPayment(ctx context.Context, req *Request) (res Response, err error) {
+	if err := validateSGK(req); err != nil {
+		return res, err
+	}
+	return api.Auth(ctx, req)
+}
@@ -0,0 +1,20 @@
+package akbankpos
+
+import "testing"
+
+func TestSetSGKSurcharge(t *testing.T) {
+	req := new(Request)
+	if err := req.SetSGKSurcharge("5"); err == nil {
+		t.Fatal("expected error when amount is not set")
+	}
+	req.SetAmount("100", "TRY")
+	if err := req.SetSGKSurcharge("x"); err == nil || req.SGK != nil {
+		t.Fatalf("err = %v, sgk = %+v", err, req.SGK)
+	}
+	if err := req.SetSGKSurcharge("2.50"); err != nil {
+		t.Fatal(err)
+	}
+	if err := validateSGK(req); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,104 @@
+package akbankpos
+
+import "sync"
+
+type SubMerchantRegistry struct {
+	mu      sync.RWMutex
+	sellers map[string]string
+	orders  map[string]string
+}
+
+func NewSubMerchantRegistry() *SubMerchantRegistry {
+	return &SubMerchantRegistry{
+		sellers: make(map[string]string),
+		orders:  make(map[string]string),
+	}
+}
+
+func WithSubMerchants(registry *SubMerchantRegistry) Option {
+	return func(api *API) {
+		api.SubMerchants = registry
+	}
+}
+
+func (req *Request) SetSubMerchant(submerchantid string) {
+	if req.SubMerchant == nil {
+		req.SubMerchant = new(SubMerchant)
+	}
+	req.SubMerchant.SubMerchantId = &submerchantid
+}
+
+func (r *SubMerchantRegistry) Register(sellerid, submerchantid string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.sellers == nil {
+		r.sellers = make(map[string]string)
+	}
+	r.sellers[sellerid] = submerchantid
+}
+
+func (r *SubMerchantRegistry) Lookup(sellerid string) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	submerchantid, ok := r.sellers[sellerid]
+	return submerchantid, ok
+}
+
+func (r *SubMerchantRegistry) Bind(orderid, submerchantid string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.orders == nil {
+		r.orders = make(map[string]string)
+	}
+	r.orders[orderid] = submerchantid
+}
+
+func (r *SubMerchantRegistry) Order(orderid string) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	submerchantid, ok := r.orders[orderid]
+	return submerchantid, ok
+}
+
+func (r *SubMerchantRegistry) Apply(req *Request, sellerid string) error {
+	if req == nil {
+		return nilRequest()
+	}
+	submerchantid, ok := r.Lookup(sellerid)
+	if !ok {
+		return &ValidationError{Errors: []Errors{{Code: "subMerchantId", Message: "no sub-merchant registered for seller " + sellerid}}}
+	}
+	req.SetSubMerchant(submerchantid)
+	return r.Check(req)
+}
+
+func (r *SubMerchantRegistry) Check(req *Request) error {
+	if req == nil {
+		return nil
+	}
+	if req.Order == nil || value(req.Order.OrderId) == "" {
+		return nil
+	}
+	bound, ok := r.Order(*req.Order.OrderId)
+	if !ok {
+		return nil
+	}
+	if req.SubMerchant == nil || value(req.SubMerchant.SubMerchantId) == "" {
+		req.SetSubMerchant(bound)
+		return nil
+	}
+	if *req.SubMerchant.SubMerchantId != bound {
+		return &ValidationError{Errors: []Errors{{Code: "subMerchantId", Message: "sub-merchant does not match the original sale of order " + *req.Order.OrderId}}}
+	}
+	return nil
+}
+
+func (r *SubMerchantRegistry) record(req *Request) {
+	if req == nil || req.Order == nil || value(req.Order.OrderId) == "" || req.SubMerchant == nil || value(req.SubMerchant.SubMerchantId) == "" {
+		return
+	}
+	switch value(req.TxnCode) {
+	case "1000", "1004":
+		r.Bind(*req.Order.OrderId, *req.SubMerchant.SubMerchantId)
+	}
+}
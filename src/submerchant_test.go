@@ -0,0 +1,80 @@
+package akbankpos
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSubMerchantRegistryZeroValue(t *testing.T) {
+	var registry SubMerchantRegistry
+	if _, ok := registry.Lookup("seller"); ok {
+		t.Fatal("empty registry found a seller")
+	}
+	registry.Register("seller", "sub-1")
+	registry.Bind("order-1", "sub-1")
+	if id, ok := registry.Lookup("seller"); !ok || id != "sub-1" {
+		t.Fatalf("lookup = %q, %v", id, ok)
+	}
+	if id, ok := registry.Order("order-1"); !ok || id != "sub-1" {
+		t.Fatalf("order = %q, %v", id, ok)
+	}
+}
+
+func TestSubMerchantConsistency(t *testing.T) {
+	registry := NewSubMerchantRegistry()
+	api, bank := newBank(t, respond(`{"responseCode":"VPS-0000"}`), WithSubMerchants(registry))
+	sale := api.NewRequest()
+	sale.SetCardNumber("4355084355084358")
+	sale.SetCardExpiry("12", "99")
+	sale.SetAmount("10", "TRY")
+	sale.SetOrderId("order-1")
+	sale.SetSubMerchant("sub-1")
+	if _, err := api.Auth(context.Background(), sale); err != nil {
+		t.Fatal(err)
+	}
+	refund := api.NewRequest()
+	refund.SetOrderId("order-1")
+	refund.SetAmount("5", "TRY")
+	refund.SetSubMerchant("sub-2")
+	var verr *ValidationError
+	if _, err := api.Refund(context.Background(), refund); !errors.As(err, &verr) {
+		t.Fatalf("refund with another sub-merchant: err = %v", err)
+	}
+	cancel := api.NewRequest()
+	cancel.SetOrderId("order-1")
+	if _, err := api.Cancel(context.Background(), cancel); err != nil {
+		t.Fatal(err)
+	}
+	requests := bank.requests()
+	if len(requests) != 2 {
+		t.Fatalf("sent %d requests, want 2", len(requests))
+	}
+	if sub, _ := requests[1]["subMerchant"].(map[string]interface{}); sub["subMerchantId"] != "sub-1" || requests[1]["txnCode"] != "1003" {
+		t.Fatalf("cancel sent %v", requests[1])
+	}
+	if cancel.SubMerchant != nil {
+		t.Fatal("caller's request was changed")
+	}
+	registry.Bind("order-3d", "sub-1")
+	secure := api.NewRequest()
+	secure.SetCardNumber("4355084355084358")
+	secure.SetCardExpiry("12", "99")
+	secure.SetAmount("10", "TRY")
+	secure.SetOrderId("order-3d")
+	secure.SetSubMerchant("sub-2")
+	okurl, failurl := "https://example.com/ok", "https://example.com/fail"
+	secure.OkUrl, secure.FailUrl = &okurl, &failurl
+	if _, err := api.Auth3Dhtml(context.Background(), secure); !errors.As(err, &verr) {
+		t.Fatalf("3d form with another sub-merchant: err = %v", err)
+	}
+	secure.SubMerchant = nil
+	form, err := api.Auth3Dhtml(context.Background(), secure)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(D64(form)), `name="subMerchantId" value="sub-1"`) {
+		t.Fatal("3d form was not filled with the bound sub-merchant")
+	}
+}
@@ -0,0 +1,142 @@
+package akbankpos
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+func nilRequest() error {
+	return &ValidationError{Errors: []Errors{{Code: "request", Message: "request is required"}}}
+}
+
+func Luhn(number string) bool {
+	if !digits(number) {
+		return false
+	}
+	sum := 0
+	double := false
+	for i := len(number) - 1; i >= 0; i-- {
+		n := int(number[i] - '0')
+		if double {
+			n *= 2
+			if n > 9 {
+				n -= 9
+			}
+		}
+		sum += n
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+func expired(expiry string, now time.Time) bool {
+	month, _ := strconv.Atoi(expiry[:2])
+	year, _ := strconv.Atoi(expiry[2:])
+	year += 2000
+	return year < now.Year() || (year == now.Year() && month < int(now.Month()))
+}
+
+func (req *Request) validateCard(errs []Errors, now time.Time) []Errors {
+	if req.Card == nil {
+		return append(errs, Errors{Code: "card", Message: "card is required"})
+	}
+	number := value(req.Card.CardNumber)
+	switch {
+	case number == "":
+		errs = append(errs, Errors{Code: "cardNumber", Message: "card number is required"})
+	case !digits(number) || len(number) < 12 || len(number) > 19:
+		errs = append(errs, Errors{Code: "cardNumber", Message: "card number must be 12-19 digits"})
+	case !Luhn(number):
+		errs = append(errs, Errors{Code: "cardNumber", Message: "card number fails the Luhn check"})
+	}
+	expiry := value(req.Card.CardExpiry)
+	switch {
+	case expiry == "":
+		errs = append(errs, Errors{Code: "expireDate", Message: "card expiry is required"})
+	case len(expiry) != 4 || !digits(expiry) || expiry[:2] < "01" || expiry[:2] > "12":
+		errs = append(errs, Errors{Code: "expireDate", Message: "card expiry must be in MMYY format"})
+	case expired(expiry, now.In(Istanbul)):
+		errs = append(errs, Errors{Code: "expireDate", Message: "card has expired"})
+	}
+	if req.Card.CardCode != nil {
+		cvv := *req.Card.CardCode
+		length := 3
+		if strings.HasPrefix(number, "34") || strings.HasPrefix(number, "37") {
+			length = 4
+		}
+		if !digits(cvv) || len(cvv) != length {
+			errs = append(errs, Errors{Code: "cvv2", Message: "cvv must be " + strconv.Itoa(length) + " digits"})
+		}
+	}
+	return errs
+}
+
+func (req *Request) validateAmount(errs []Errors) []Errors {
+	if req.Transaction == nil || req.Transaction.Amount == nil {
+		return append(errs, Errors{Code: "amount", Message: "amount is required"})
+	}
+	if req.Transaction.Amount.Minor <= 0 {
+		errs = append(errs, Errors{Code: "amount", Message: "amount must be greater than zero"})
+	}
+	if req.Transaction.Currency == nil || !req.Transaction.Currency.Valid() {
+		errs = append(errs, Errors{Code: "currencyCode", Message: "currency is required"})
+	}
+	return errs
+}
+
+func (req *Request) validateOrder(errs []Errors, track bool) []Errors {
+	if req.Order != nil && value(req.Order.OrderId) != "" {
+		return errs
+	}
+	if track && req.Order != nil && value(req.Order.OrderTrackId) != "" {
+		return errs
+	}
+	return append(errs, Errors{Code: "orderId", Message: "order id is required"})
+}
+
+func (req *Request) Validate(txncode string) error {
+	return req.ValidateAt(txncode, time.Now())
+}
+
+func (req *Request) ValidateAt(txncode string, now time.Time) error {
+	if req == nil {
+		return nilRequest()
+	}
+	errs := []Errors{}
+	if req.Terminal == nil || value(req.Terminal.MerchantSafeId) == "" || value(req.Terminal.TerminalSafeId) == "" {
+		errs = append(errs, Errors{Code: "terminal", Message: "merchant and terminal ids are required"})
+	}
+	switch txncode {
+	case "1000", "1004", "3000", "3004":
+		if req.SecureTransaction == nil && req.InsurancePan == nil {
+			errs = req.validateCard(errs, now)
+		}
+		errs = req.validateAmount(errs)
+		if req.Transaction != nil && req.Transaction.Installment != nil {
+			if n := *req.Transaction.Installment; n < 1 || n > 12 {
+				errs = append(errs, Errors{Code: "installCount", Message: "installment count must be between 1 and 12"})
+			}
+		}
+		if req.SecureTransaction != nil {
+			errs = req.validateOrder(errs, false)
+		}
+		if req.B2B != nil && !ValidIdentityNumber(value(req.B2B.IdentityNumber)) {
+			errs = append(errs, Errors{Code: "b2bIdentityNumber", Message: "identity number must be a valid 10 digit VKN or 11 digit TCKN"})
+		}
+		if txncode == "3000" || txncode == "3004" {
+			if value(req.OkUrl) == "" || value(req.FailUrl) == "" {
+				errs = append(errs, Errors{Code: "okUrl", Message: "okUrl and failUrl are required"})
+			}
+		}
+	case "1002", "1005", "1006":
+		errs = req.validateOrder(errs, false)
+		errs = req.validateAmount(errs)
+	case "1003", "1010":
+		errs = req.validateOrder(errs, true)
+	}
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package akbankpos
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestExpiryUsesClock(t *testing.T) {
+	for _, test := range []struct {
+		now     time.Time
+		expired bool
+	}{
+		{time.Date(2025, 12, 31, 23, 0, 0, 0, Istanbul), false},
+		{time.Date(2026, 1, 1, 0, 0, 0, 0, Istanbul), true},
+	} {
+		now := test.now
+		api := New("merchant", "terminal", "secret", WithClock(ClockFunc(func() time.Time { return now })), WithBaseURL("http://127.0.0.1:0"))
+		req := api.NewRequest()
+		req.SetCardNumber("4355084355084358")
+		req.SetCardExpiry("12", "25")
+		req.SetAmount("10", "TRY")
+		_, err := api.Auth(context.Background(), req)
+		var verr *ValidationError
+		if got := errors.As(err, &verr); got != test.expired {
+			t.Fatalf("%s: err = %v, want expired %v", now, err, test.expired)
+		}
+	}
+}
+
+func TestSetInstallmentInvalid(t *testing.T) {
+	req := new(Request)
+	if err := req.SetInstallment("x"); err == nil {
+		t.Fatal("expected error")
+	}
+	if req.Transaction != nil {
+		t.Fatal("invalid installment changed the request")
+	}
+	if err := req.SetInstallment("3"); err != nil || *req.Transaction.Installment != 3 {
+		t.Fatalf("err = %v", err)
+	}
+}
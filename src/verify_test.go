@@ -0,0 +1,74 @@
+package akbankpos
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func signed(api *API, body string) string {
+	values := url.Values{
+		"txnCode":          {"1000"},
+		"responseCode":     {"VPS-0000"},
+		"hostResponseCode": {"00"},
+		"merchantSafeId":   {"merchant"},
+		"terminalSafeId":   {"terminal"},
+		"orderId":          {"order-1"},
+		"amount":           {"10.00"},
+		"currencyCode":     {"949"},
+	}
+	return strings.Replace(body, `"hash":""`, `"hash":"`+api.Hash3D(values, ResponseHashParams)+`"`, 1)
+}
+
+const signedBody = `{"txnCode":"1000","responseCode":"VPS-0000","hostResponseCode":"00","hash":"","terminal":{"merchantSafeId":"merchant","terminalSafeId":"terminal"},"order":{"orderId":"order-1"},"transaction":{"amount":10.00,"currencyCode":949},"linkDetail":{"amount":99.00,"currencyCode":840}}`
+
+func TestVerifyHashHeader(t *testing.T) {
+	api := New("merchant", "terminal", "secret")
+	body := []byte(`{"responseCode":"VPS-0000"}`)
+	if !api.VerifyHash(api.Hash(body), body) {
+		t.Fatal("valid header hash rejected")
+	}
+	if api.VerifyHash(api.Hash(body), []byte(`{"responseCode":"VPS-0001"}`)) {
+		t.Fatal("tampered body accepted with header hash")
+	}
+}
+
+func TestVerifyHashBody(t *testing.T) {
+	api := New("merchant", "terminal", "secret")
+	body := signed(api, signedBody)
+	for i := 0; i < 200; i++ {
+		if !api.VerifyHash("", []byte(body)) {
+			t.Fatalf("valid body hash rejected on attempt %d", i)
+		}
+	}
+	if api.VerifyHash("", []byte(strings.Replace(body, `"amount":10.00`, `"amount":1.00`, 1))) {
+		t.Fatal("tampered body accepted")
+	}
+	if api.VerifyHash("", []byte(signedBody)) {
+		t.Fatal("body without hash accepted")
+	}
+	if api.VerifyHash("", []byte(`{"responseCode":"VPS-0000"}`)) {
+		t.Fatal("body without hash field accepted")
+	}
+}
+
+func TestVerifyResponse(t *testing.T) {
+	api := New("merchant", "terminal", "secret")
+	body := signed(api, signedBody)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+	api = New("merchant", "terminal", "secret", WithBaseURL(server.URL), WithVerifyResponse(true))
+	if _, err := api.QueryOrder(context.Background(), "order-1"); err != nil {
+		t.Fatal(err)
+	}
+	body = strings.Replace(body, `"amount":10.00`, `"amount":1.00`, 1)
+	if _, err := api.QueryOrder(context.Background(), "order-1"); !errors.Is(err, ErrResponseSignature) {
+		t.Fatalf("err = %v, want %v", err, ErrResponseSignature)
+	}
+}